@@ -52,12 +52,27 @@ const (
 	WaitTested
 	Retry
 	Fail
+	Golden
+	Eventually
+	Consistently
 )
 
 //--------------------
 // ASSERTION
 //--------------------
 
+// Matcher tests a polled value by using the passed assertion,
+// e.g.
+//
+//     func(assert audit.Assertion, obtained interface{}) bool {
+//         return assert.Length(obtained, 5)
+//     }
+type Matcher func(assert Assertion, obtained interface{}) bool
+
+// pollPause is the pause between two polls of the Eventually
+// and Consistently assertions.
+const pollPause = 10 * time.Millisecond
+
 // MakeSigChan is a simple one-liner to create the buffered signal channel
 // for the wait assertion.
 func MakeSigChan() chan interface{} {
@@ -100,7 +115,9 @@ type Assertion interface {
 	// NotNil tests if obtained is not nil.
 	NotNil(obtained interface{}, msgs ...string) bool
 
-	// Equal tests if obtained and expected are equal.
+	// Equal tests if obtained and expected are equal. In case of
+	// structs, maps, slices, arrays, or pointers the failure output
+	// contains the differences per path.
 	Equal(obtained, expected interface{}, msgs ...string) bool
 
 	// Different tests if obtained and expected are different.
@@ -176,6 +193,24 @@ type Assertion interface {
 	// it pauses for the given duration and retries the call the defined number.
 	Retry(rf func() bool, retries int, pause time.Duration, msgs ...string) bool
 
+	// Eventually polls a value with the passed function and tests it
+	// with the matcher until it matches or the timeout is reached. In
+	// the latter case the last obtained value and failure are reported.
+	Eventually(pf func() interface{}, m Matcher, timeout time.Duration, msgs ...string) bool
+
+	// Consistently polls a value with the passed function and tests it
+	// with the matcher for the given duration. The first failing value
+	// is reported.
+	Consistently(pf func() interface{}, m Matcher, duration time.Duration, msgs ...string) bool
+
+	// Golden tests if the obtained value is equal to the content of the
+	// golden file with the given name inside the testdata directory, e.g.
+	// "testdata/<name>.golden". Strings, byte slices, and stringers are
+	// taken directly, other values are formatted with %v. If the
+	// environment variable AUDIT_UPDATE_GOLDEN is set to true the
+	// golden file is written instead.
+	Golden(obtained interface{}, name string, msgs ...string) bool
+
 	// Fail always fails.
 	Fail(msgs ...string) bool
 }
@@ -469,6 +504,60 @@ func (a *assertion) Retry(rf func() bool, retries int, pause time.Duration, msgs
 	return a.failer.Fail(Retry, info, "successful call", msgs...)
 }
 
+// Eventually implements Assertion.
+func (a *assertion) Eventually(pf func() interface{}, m Matcher, timeout time.Duration, msgs ...string) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		obtained := pf()
+		ok, failure := match(m, obtained)
+		if ok {
+			return true
+		}
+		if time.Now().After(deadline) {
+			info := fmt.Sprintf("timeout after %v: %s", timeout, failure)
+			return a.failer.Fail(Eventually, obtained, info, msgs...)
+		}
+		time.Sleep(pollPause)
+	}
+}
+
+// Consistently implements Assertion.
+func (a *assertion) Consistently(pf func() interface{}, m Matcher, duration time.Duration, msgs ...string) bool {
+	deadline := time.Now().Add(duration)
+	for {
+		obtained := pf()
+		ok, failure := match(m, obtained)
+		if !ok {
+			return a.failer.Fail(Consistently, obtained, failure, msgs...)
+		}
+		if time.Now().After(deadline) {
+			return true
+		}
+		time.Sleep(pollPause)
+	}
+}
+
+// Golden implements Assertion.
+func (a *assertion) Golden(obtained interface{}, name string, msgs ...string) bool {
+	content := goldenContent(obtained)
+	path := GoldenPath(name)
+	if UpdateGolden() {
+		if err := writeGolden(path, content); err != nil {
+			return a.failer.Fail(Golden, content, &goldenDiff{path, ""}, "cannot write golden file: "+err.Error())
+		}
+		return true
+	}
+	expected, err := readGolden(path)
+	if err != nil {
+		return a.failer.Fail(Golden, content, &goldenDiff{path, ""}, "cannot read golden file: "+err.Error())
+	}
+	diff := LineDiff(content, expected)
+	if diff != "" {
+		return a.failer.Fail(Golden, content, &goldenDiff{path, diff}, msgs...)
+	}
+	return true
+}
+
 // Fail implements Assertion.
 func (a *assertion) Fail(msgs ...string) bool {
 	return a.failer.Fail(Fail, nil, nil, msgs...)
@@ -489,6 +578,20 @@ type lenable interface {
 	Len() int
 }
 
+// match tests the obtained value with the matcher and returns
+// the failure in case it doesn't match.
+func match(m Matcher, obtained interface{}) (bool, string) {
+	va, failures := NewValidationAssertion()
+	if m(va, obtained) && !failures.HasErrors() {
+		return true, ""
+	}
+	if !failures.HasErrors() {
+		return false, "matcher returned false"
+	}
+	errs := failures.Errors()
+	return false, errs[len(errs)-1].Error()
+}
+
 // obexString constructs a descriptive sting matching
 // to test, obtained, and expected value.
 func obexString(test Test, obtained, expected interface{}) string {
@@ -500,6 +603,14 @@ func obexString(test Test, obtained, expected interface{}) string {
 	case Range:
 		lh := expected.(*lowHigh)
 		return fmt.Sprintf("not '%v' <= '%v' <= '%v'", lh.low, obtained, lh.high)
+	case Eventually, Consistently:
+		return fmt.Sprintf("'%v' (%v)", obtained, expected)
+	case Golden:
+		gd := expected.(*goldenDiff)
+		if gd.diff == "" {
+			return fmt.Sprintf("'%s'", gd.path)
+		}
+		return fmt.Sprintf("'%s' differs:\n%s", gd.path, gd.diff)
 	case Fail:
 		return "fail intended"
 	default:
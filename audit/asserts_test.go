@@ -468,6 +468,22 @@ func TestSetFailable(t *testing.T) {
 	})
 }
 
+// TestTests tests the values and names of the tests. New
+// ones must not change the values of the existing ones.
+func TestTests(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	assert.Equal(int(audit.Retry), 25)
+	assert.Equal(int(audit.Fail), 26)
+	assert.Equal(int(audit.Golden), 27)
+	assert.Equal(int(audit.Eventually), 28)
+	assert.Equal(int(audit.Consistently), 29)
+	assert.Equal(audit.Fail.String(), "fail")
+	assert.Equal(audit.Golden.String(), "golden")
+	assert.Equal(audit.Eventually.String(), "eventually")
+	assert.Equal(audit.Consistently.String(), "consistently")
+}
+
 //--------------------
 // META FAILER
 //--------------------
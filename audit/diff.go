@@ -0,0 +1,404 @@
+// Tideland Go Library - Audit
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+//--------------------
+// CONSTANTS
+//--------------------
+
+const (
+	// diffContext is the number of unchanged lines shown
+	// around changed ones.
+	diffContext = 2
+
+	// diffLimit is the maximum number of differences collected
+	// by the value diff.
+	diffLimit = 50
+)
+
+//--------------------
+// LINE DIFF
+//--------------------
+
+// lineOp describes one operation of a line based diff.
+type lineOp struct {
+	kind     byte
+	line     string
+	expected int
+	obtained int
+}
+
+// LineDiff compares the obtained and the expected text line by
+// line. The result is empty if both are equal. Otherwise it shows
+// the changed lines with a leading minus for expected and a leading
+// plus for obtained lines, each with the line number and some
+// context lines.
+func LineDiff(obtained, expected string) string {
+	if obtained == expected {
+		return ""
+	}
+	ops := lineOps(splitLines(obtained), splitLines(expected))
+	// Mark the lines to show.
+	show := make([]bool, len(ops))
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		lo := i - diffContext
+		if lo < 0 {
+			lo = 0
+		}
+		hi := i + diffContext
+		if hi >= len(ops) {
+			hi = len(ops) - 1
+		}
+		for j := lo; j <= hi; j++ {
+			show[j] = true
+		}
+	}
+	// Render the marked lines.
+	buffer := &bytes.Buffer{}
+	skipped := false
+	for i, op := range ops {
+		if !show[i] {
+			skipped = true
+			continue
+		}
+		if skipped && buffer.Len() > 0 {
+			fmt.Fprintf(buffer, "     ...\n")
+		}
+		skipped = false
+		switch op.kind {
+		case '-':
+			fmt.Fprintf(buffer, "- %4d: %s\n", op.expected, op.line)
+		case '+':
+			fmt.Fprintf(buffer, "+ %4d: %s\n", op.obtained, op.line)
+		default:
+			fmt.Fprintf(buffer, "  %4d: %s\n", op.expected, op.line)
+		}
+	}
+	return buffer.String()
+}
+
+// splitLines splits a text into its lines.
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// lineOps calculates the operations to get from the expected
+// to the obtained lines based on their longest common subsequence.
+func lineOps(obtained, expected []string) []lineOp {
+	// Skip common prefix and suffix to keep the table small.
+	prefix := 0
+	for prefix < len(obtained) && prefix < len(expected) && obtained[prefix] == expected[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(obtained)-prefix && suffix < len(expected)-prefix &&
+		obtained[len(obtained)-1-suffix] == expected[len(expected)-1-suffix] {
+		suffix++
+	}
+	obs := obtained[prefix : len(obtained)-suffix]
+	exs := expected[prefix : len(expected)-suffix]
+	// Build the table of common subsequence lengths.
+	lcs := make([][]int, len(exs)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(obs)+1)
+	}
+	for i := len(exs) - 1; i >= 0; i-- {
+		for j := len(obs) - 1; j >= 0; j-- {
+			if exs[i] == obs[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	// Walk the table and collect the operations.
+	ops := []lineOp{}
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, lineOp{' ', expected[i], i + 1, i + 1})
+	}
+	i, j := 0, 0
+	for i < len(exs) || j < len(obs) {
+		switch {
+		case i < len(exs) && j < len(obs) && exs[i] == obs[j]:
+			ops = append(ops, lineOp{' ', exs[i], prefix + i + 1, prefix + j + 1})
+			i++
+			j++
+		case i < len(exs) && (j == len(obs) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, lineOp{'-', exs[i], prefix + i + 1, prefix + j + 1})
+			i++
+		default:
+			ops = append(ops, lineOp{'+', obs[j], prefix + i + 1, prefix + j + 1})
+			j++
+		}
+	}
+	for k := 0; k < suffix; k++ {
+		ei := len(expected) - suffix + k
+		oi := len(obtained) - suffix + k
+		ops = append(ops, lineOp{' ', expected[ei], ei + 1, oi + 1})
+	}
+	return ops
+}
+
+//--------------------
+// VALUE DIFF
+//--------------------
+
+// ValueDiff compares the obtained and the expected value structurally.
+// It walks into structs, including unexported fields, maps, slices,
+// arrays, pointers, and interfaces. The result contains one entry per
+// difference, each with the path to the differing value like
+//
+//     .Orders[3].Items["x"].Count: 2 != 3
+//
+// The obtained value is always on the left side. Equal values return
+// an empty slice.
+func ValueDiff(obtained, expected interface{}) []string {
+	vd := &valueDiffer{
+		visited: make(map[visit]bool),
+	}
+	vd.diff("", reflect.ValueOf(obtained), reflect.ValueOf(expected))
+	if vd.skipped > 0 {
+		vd.diffs = append(vd.diffs, fmt.Sprintf("... and %d more differences", vd.skipped))
+	}
+	return vd.diffs
+}
+
+// visit marks a pair of already compared pointers, maps, or slices.
+type visit struct {
+	obtained uintptr
+	expected uintptr
+	typ      reflect.Type
+}
+
+// valueDiffer collects the differences of two values.
+type valueDiffer struct {
+	visited map[visit]bool
+	diffs   []string
+	skipped int
+}
+
+// add adds a difference at the given path.
+func (vd *valueDiffer) add(path, obtained, expected string) {
+	if len(vd.diffs) >= diffLimit {
+		vd.skipped++
+		return
+	}
+	if path == "" {
+		path = "."
+	}
+	vd.diffs = append(vd.diffs, fmt.Sprintf("%s: %s != %s", path, obtained, expected))
+}
+
+// diff recursively compares obtained and expected.
+func (vd *valueDiffer) diff(path string, o, e reflect.Value) {
+	if !o.IsValid() || !e.IsValid() {
+		if o.IsValid() != e.IsValid() {
+			vd.add(path, diffString(o), diffString(e))
+		}
+		return
+	}
+	if o.Type() != e.Type() {
+		vd.add(path, diffTypedString(o), diffTypedString(e))
+		return
+	}
+	switch o.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if o.IsNil() || e.IsNil() {
+			if o.IsNil() != e.IsNil() {
+				vd.add(path, diffString(o), diffString(e))
+			}
+			return
+		}
+		if o.Kind() == reflect.Ptr && !vd.visit(o, e) {
+			return
+		}
+		vd.diff(path, o.Elem(), e.Elem())
+	case reflect.Struct:
+		if o.Type() == reflect.TypeOf(time.Time{}) {
+			vd.leaf(path, o, e)
+			return
+		}
+		for i := 0; i < o.NumField(); i++ {
+			name := o.Type().Field(i).Name
+			vd.diff(path+"."+name, o.Field(i), e.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if o.Kind() == reflect.Slice {
+			if o.IsNil() != e.IsNil() {
+				vd.add(path, diffString(o), diffString(e))
+				return
+			}
+			if o.Len() == e.Len() && !vd.visit(o, e) {
+				return
+			}
+		}
+		for i := 0; i < o.Len() || i < e.Len(); i++ {
+			ipath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= e.Len():
+				vd.add(ipath, diffString(o.Index(i)), "<none>")
+			case i >= o.Len():
+				vd.add(ipath, "<none>", diffString(e.Index(i)))
+			default:
+				vd.diff(ipath, o.Index(i), e.Index(i))
+			}
+		}
+	case reflect.Map:
+		if o.IsNil() != e.IsNil() {
+			vd.add(path, diffString(o), diffString(e))
+			return
+		}
+		if !vd.visit(o, e) {
+			return
+		}
+		// Keys are matched by the map itself, their strings
+		// are only used for the paths and the order.
+		keys := o.MapKeys()
+		for _, key := range e.MapKeys() {
+			if !o.MapIndex(key).IsValid() {
+				keys = append(keys, key)
+			}
+		}
+		names := make([]string, len(keys))
+		for i, key := range keys {
+			names[i] = diffTypedString(key)
+		}
+		sort.Sort(mapKeys{keys, names})
+		for _, key := range keys {
+			kpath := fmt.Sprintf("%s[%s]", path, diffString(key))
+			ov := o.MapIndex(key)
+			ev := e.MapIndex(key)
+			switch {
+			case !ev.IsValid():
+				vd.add(kpath, diffString(ov), "<none>")
+			case !ov.IsValid():
+				vd.add(kpath, "<none>", diffString(ev))
+			default:
+				vd.diff(kpath, ov, ev)
+			}
+		}
+	case reflect.Func:
+		if !o.IsNil() || !e.IsNil() {
+			vd.add(path, diffString(o), diffString(e))
+		}
+	default:
+		vd.leaf(path, o, e)
+	}
+}
+
+// visit marks the pointers, maps, or slices as visited. It returns
+// false if they are identical or have been visited before, so
+// that cyclic values are compared only once.
+func (vd *valueDiffer) visit(o, e reflect.Value) bool {
+	if o.Pointer() == e.Pointer() {
+		return false
+	}
+	v := visit{o.Pointer(), e.Pointer(), o.Type()}
+	if vd.visited[v] {
+		return false
+	}
+	vd.visited[v] = true
+	return true
+}
+
+// mapKeys sorts map keys by their typed strings.
+type mapKeys struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (mk mapKeys) Len() int           { return len(mk.keys) }
+func (mk mapKeys) Less(i, j int) bool { return mk.names[i] < mk.names[j] }
+func (mk mapKeys) Swap(i, j int) {
+	mk.keys[i], mk.keys[j] = mk.keys[j], mk.keys[i]
+	mk.names[i], mk.names[j] = mk.names[j], mk.names[i]
+}
+
+// leaf compares two values which are not walked into.
+func (vd *valueDiffer) leaf(path string, o, e reflect.Value) {
+	equal := false
+	switch o.Kind() {
+	case reflect.Bool:
+		equal = o.Bool() == e.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		equal = o.Int() == e.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		equal = o.Uint() == e.Uint()
+	case reflect.Float32, reflect.Float64:
+		equal = o.Float() == e.Float()
+	case reflect.Complex64, reflect.Complex128:
+		equal = o.Complex() == e.Complex()
+	case reflect.String:
+		equal = o.String() == e.String()
+	case reflect.Chan, reflect.UnsafePointer:
+		equal = o.Pointer() == e.Pointer()
+	default:
+		equal = diffString(o) == diffString(e)
+	}
+	if !equal {
+		vd.add(path, diffString(o), diffString(e))
+	}
+}
+
+// diffString returns the string representation of a value
+// inside a difference.
+func diffString(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("%q", v.String())
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// diffTypedString returns the string representation of a value
+// and its type inside a difference.
+func diffTypedString(v reflect.Value) string {
+	return fmt.Sprintf("%s (%s)", diffString(v), v.Type())
+}
+
+// structuralDiff returns the value diff as multi-line string
+// if obtained and expected are composite values. Otherwise
+// the plain values tell enough.
+func structuralDiff(obtained, expected interface{}) string {
+	composite := func(v interface{}) bool {
+		switch reflect.ValueOf(v).Kind() {
+		case reflect.Array, reflect.Map, reflect.Ptr, reflect.Slice, reflect.Struct:
+			return true
+		}
+		return false
+	}
+	if !composite(obtained) && !composite(expected) {
+		return ""
+	}
+	return strings.Join(ValueDiff(obtained, expected), "\n")
+}
+
+// EOF
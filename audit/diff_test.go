@@ -0,0 +1,149 @@
+// Tideland Go Library - Audit - Unit Tests
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestLineDiff tests the line based comparison of texts.
+func TestLineDiff(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	assert.Empty(audit.LineDiff("a\nb\nc", "a\nb\nc"))
+	assert.Equal(audit.LineDiff("a\nx\nc", "a\nb\nc"), "     1: a\n-    2: b\n+    2: x\n     3: c\n")
+	assert.Equal(audit.LineDiff("a\nb\nc\nd", "a\nb\nc"), "     2: b\n     3: c\n+    4: d\n")
+	assert.Equal(audit.LineDiff("", "a"), "-    1: a\n")
+
+	obtained := "0\n2\n3\n4\n5\n6\n7\n8\n0"
+	expected := "1\n2\n3\n4\n5\n6\n7\n8\n9"
+	diff := audit.LineDiff(obtained, expected)
+	assert.Equal(diff, "-    1: 1\n+    1: 0\n     2: 2\n     3: 3\n     ...\n     7: 7\n     8: 8\n-    9: 9\n+    9: 0\n")
+}
+
+// TestValueDiff tests the structural comparison of values.
+func TestValueDiff(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	type item struct {
+		Count int
+		note  string
+	}
+	type order struct {
+		ID    int
+		Items map[string]*item
+		Tags  []string
+	}
+	type customer struct {
+		Name   string
+		Orders []order
+		Since  time.Time
+	}
+	now := time.Date(2017, time.September, 9, 12, 0, 0, 0, time.UTC)
+	build := func() *customer {
+		return &customer{
+			Name: "John",
+			Orders: []order{
+				{1, map[string]*item{"x": {2, "foo"}, "y": {1, "bar"}}, []string{"a"}},
+				{2, map[string]*item{}, nil},
+			},
+			Since: now,
+		}
+	}
+
+	assert.Empty(audit.ValueDiff(build(), build()))
+	assert.Empty(audit.ValueDiff(nil, nil))
+
+	obtained := build()
+	obtained.Orders[0].Items["x"].Count = 3
+	obtained.Orders[0].Items["x"].note = "baz"
+	obtained.Orders[0].Tags = append(obtained.Orders[0].Tags, "b")
+	obtained.Orders[1].Items["z"] = &item{5, ""}
+	obtained.Since = now.Add(time.Hour)
+	assert.Equal(audit.ValueDiff(obtained, build()), []string{
+		`.Orders[0].Items["x"].Count: 3 != 2`,
+		`.Orders[0].Items["x"].note: "baz" != "foo"`,
+		`.Orders[0].Tags[1]: "b" != <none>`,
+		`.Orders[1].Items["z"]: &{5 } != <none>`,
+		`.Since: 2017-09-09 13:00:00 +0000 UTC != 2017-09-09 12:00:00 +0000 UTC`,
+	})
+
+	obtained = build()
+	obtained.Orders[1].Tags = []string{}
+	obtained.Orders = obtained.Orders[:1]
+	assert.Equal(audit.ValueDiff(obtained, build()), []string{
+		`.Orders[1]: <none> != {2 map[] []}`,
+	})
+
+	assert.Equal(audit.ValueDiff(1, "1"), []string{`.: 1 (int) != "1" (string)`})
+	assert.Equal(audit.ValueDiff([]int{}, []int(nil)), []string{`.: [] != []`})
+	assert.Equal(audit.ValueDiff(map[int]int{1: 1, 2: 2}, map[int]int{1: 1, 2: 3}), []string{`[2]: 2 != 3`})
+
+	many := make([]int, 100)
+	assert.Length(audit.ValueDiff(many, make([]int, 0)), 51)
+}
+
+// TestValueDiffMaps tests the matching of map keys by
+// equality instead of their string representation.
+func TestValueDiffMaps(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	obtained := map[interface{}]string{1: "a", int64(1): "b"}
+	expected := map[interface{}]string{1: "a", int64(1): "c"}
+	assert.Equal(audit.ValueDiff(obtained, expected), []string{`[1]: "b" != "c"`})
+
+	obtained = map[interface{}]string{1: "a"}
+	expected = map[interface{}]string{int64(1): "a"}
+	assert.Equal(audit.ValueDiff(obtained, expected), []string{
+		`[1]: "a" != <none>`,
+		`[1]: <none> != "a"`,
+	})
+}
+
+// TestValueDiffCycles tests the comparing of self-referencing
+// maps and slices.
+func TestValueDiffCycles(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	om := map[string]interface{}{"x": 1}
+	om["self"] = om
+	em := map[string]interface{}{"x": 2}
+	em["self"] = em
+	assert.Equal(audit.ValueDiff(om, em), []string{`["x"]: 1 != 2`})
+
+	os := []interface{}{nil, 1}
+	os[0] = os
+	es := []interface{}{nil, 2}
+	es[0] = es
+	assert.Equal(audit.ValueDiff(os, es), []string{`[1]: 1 != 2`})
+}
+
+// TestValidationValueDiff tests the structural difference in
+// the failure details of a validation assertion.
+func TestValidationValueDiff(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	validation, failures := audit.NewValidationAssertion()
+
+	validation.Equal(map[string][]int{"a": {1, 2}}, map[string][]int{"a": {1, 3}}, "different")
+	assert.Length(failures.Details(), 1)
+	assert.Equal(failures.Details()[0].Message(), "different\n[\"a\"][1]: 2 != 3")
+	validation.Equal(1, 2)
+	assert.Equal(failures.Details()[1].Message(), "")
+}
+
+// EOF
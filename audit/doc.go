@@ -26,6 +26,10 @@
 // Additional helpers support in generating test data or work with the
 // environment, like temporary directories or environment variables, in
 // a safe and convenient way.
+//
+// The PropertyChecker calls properties, functions returning a bool or an
+// error, with generated arguments. Failing arguments are shrunk before
+// being reported together with the seed for reproduction.
 package audit
 
 // EOF
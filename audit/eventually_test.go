@@ -0,0 +1,85 @@
+// Tideland Go Library - Audit - Unit Tests
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestAssertEventually tests the Eventually() assertion.
+func TestAssertEventually(t *testing.T) {
+	successfulAssert := successfulAssertion(t)
+	failingAssert := failingAssertion(t)
+	counter := int64(0)
+	count := func() interface{} {
+		return atomic.AddInt64(&counter, 1)
+	}
+	equalFive := func(assert audit.Assertion, obtained interface{}) bool {
+		return assert.Equal(obtained, int64(5))
+	}
+	isFalse := func(assert audit.Assertion, obtained interface{}) bool {
+		return false
+	}
+
+	successfulAssert.Eventually(count, equalFive, time.Second, "should not fail")
+	failingAssert.Eventually(count, equalFive, 50*time.Millisecond, "should fail and be logged")
+	failingAssert.Eventually(count, isFalse, 50*time.Millisecond, "should fail and be logged")
+}
+
+// TestAssertConsistently tests the Consistently() assertion.
+func TestAssertConsistently(t *testing.T) {
+	successfulAssert := successfulAssertion(t)
+	failingAssert := failingAssertion(t)
+	counter := int64(0)
+	count := func() interface{} {
+		return atomic.AddInt64(&counter, 1)
+	}
+	belowTen := func(assert audit.Assertion, obtained interface{}) bool {
+		return assert.True(obtained.(int64) < 10)
+	}
+
+	successfulAssert.Consistently(count, belowTen, 50*time.Millisecond, "should not fail")
+	failingAssert.Consistently(count, belowTen, time.Second, "should fail and be logged")
+}
+
+// TestValidationEventually tests the reported detail of the
+// Eventually() assertion.
+func TestValidationEventually(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	va, failures := audit.NewValidationAssertion()
+	value := func() interface{} {
+		return []int{1, 2, 3}
+	}
+	lengthFive := func(assert audit.Assertion, obtained interface{}) bool {
+		return assert.Length(obtained, 5)
+	}
+
+	start := time.Now()
+	va.Eventually(value, lengthFive, 50*time.Millisecond)
+	assert.True(time.Since(start) >= 50*time.Millisecond)
+	assert.Length(failures.Details(), 1)
+	detail := failures.Details()[0]
+	assert.Equal(detail.Test(), audit.Eventually)
+	assert.True(strings.Contains(detail.Error().Error(), "timeout after 50ms"))
+	assert.True(strings.Contains(detail.Error().Error(), "length"))
+}
+
+// EOF
@@ -53,10 +53,18 @@ type FailureDetail interface {
 	// Test tells which kind of test has failed.
 	Test() Test
 
+	// Obtained returns the obtained value of the failed test.
+	Obtained() interface{}
+
+	// Expected returns the expected value of the failed test.
+	Expected() interface{}
+
 	// Error returns the failure as error.
 	Error() error
 
-	// Message return the optional test message.
+	// Message return the optional test message. Failing equality
+	// tests of composite values also add the structural difference
+	// of obtained and expected value, one line per path.
 	Message() string
 }
 
@@ -67,6 +75,8 @@ type failureDetail struct {
 	lineNumber int
 	funcName   string
 	test       Test
+	obtained   interface{}
+	expected   interface{}
 	err        error
 	message    string
 }
@@ -86,6 +96,16 @@ func (d *failureDetail) Test() Test {
 	return d.test
 }
 
+// Obtained implements the FailureDetail interface.
+func (d *failureDetail) Obtained() interface{} {
+	return d.obtained
+}
+
+// Expected implements the FailureDetail interface.
+func (d *failureDetail) Expected() interface{} {
+	return d.expected
+}
+
 // Error implements the FailureDetail interface.
 func (d *failureDetail) Error() error {
 	return d.err
@@ -222,14 +242,22 @@ func (f *validationFailer) Fail(test Test, obtained, expected interface{}, msgs
 	funcName := funcNameParts[funcNamePartsIdx]
 	obex := obexString(test, obtained, expected)
 	err := errors.New(failString(test, obex, msgs...))
+	message := strings.Join(msgs, " ")
+	if test == Equal {
+		if diff := structuralDiff(obtained, expected); diff != "" {
+			message = strings.TrimSpace(message + "\n" + diff)
+		}
+	}
 	detail := &failureDetail{
 		timestamp:  time.Now(),
 		fileName:   fileName,
 		lineNumber: line,
 		funcName:   funcName,
 		test:       test,
+		obtained:   obtained,
+		expected:   expected,
 		err:        err,
-		message:    strings.Join(msgs, " "),
+		message:    message,
 	}
 	f.details = append(f.details, detail)
 	f.errs = append(f.errs, err)
@@ -325,6 +353,21 @@ func (f *testingFailer) Fail(test Test, obtained, expected interface{}, msgs ...
 			fmt.Fprintf(buffer, "Part.......: %v\n", obtained)
 			fmt.Fprintf(buffer, "Full.......: %v\n", expected)
 		}
+	case Eventually, Consistently:
+		fmt.Fprintf(buffer, "Obtained...: %v\n", obtained)
+		fmt.Fprintf(buffer, "Failure....: %v\n", expected)
+	case Golden:
+		gd := expected.(*goldenDiff)
+		fmt.Fprintf(buffer, "Golden.....: %s\n", gd.path)
+		if gd.diff != "" {
+			fmt.Fprintf(buffer, "Difference.:\n%s", gd.diff)
+		}
+	case Equal:
+		fmt.Fprintf(buffer, "Obtained...: %v\n", obtained)
+		fmt.Fprintf(buffer, "Expected...: %v\n", expected)
+		if diff := structuralDiff(obtained, expected); diff != "" {
+			fmt.Fprintf(buffer, "Difference.: %s\n", strings.Replace(diff, "\n", "\n             ", -1))
+		}
 	case Fail:
 	default:
 		fmt.Fprintf(buffer, "Obtained...: %v\n", obtained)
@@ -0,0 +1,304 @@
+// Tideland Go Library - Audit
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//--------------------
+// FILE TREE
+//--------------------
+
+// FileTree describes directories and files. The keys are names or
+// slash separated paths, the values describe the entries. Strings
+// and byte slices are file contents, File also contains the mode.
+// FileTree values are directories, Dir also contains the mode.
+//
+//	tree := audit.FileTree{
+//	    "config": audit.FileTree{
+//	        "app.conf": "key = value\n",
+//	    },
+//	    "data/index.html": audit.File{"<html></html>", 0644},
+//	    "logs":            audit.Dir{0755, nil},
+//	}
+//
+// Default modes are 0700 for directories and 0600 for files. The
+// package audit/filetree reads file trees described in SML.
+type FileTree map[string]interface{}
+
+// File describes a file with content and mode.
+type File struct {
+	Content string
+	Mode    os.FileMode
+}
+
+// Dir describes a directory with mode and entries.
+type Dir struct {
+	Mode    os.FileMode
+	Entries FileTree
+}
+
+// ReadFileTree reads the directory into a file tree. All
+// directories and files are described with their modes.
+func ReadFileTree(dir string) (FileTree, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	tree := FileTree{}
+	for _, info := range infos {
+		name := filepath.Join(dir, info.Name())
+		switch {
+		case info.IsDir():
+			entries, err := ReadFileTree(name)
+			if err != nil {
+				return nil, err
+			}
+			tree[info.Name()] = Dir{info.Mode().Perm(), entries}
+		case info.Mode().IsRegular():
+			content, err := ioutil.ReadFile(name)
+			if err != nil {
+				return nil, err
+			}
+			tree[info.Name()] = File{string(content), info.Mode().Perm()}
+		}
+	}
+	return tree, nil
+}
+
+// WriteFileTree creates the directories and files of the tree
+// inside the directory.
+func WriteFileTree(dir string, tree FileTree) error {
+	entries, err := flattenFileTree(tree)
+	if err != nil {
+		return err
+	}
+	paths := entries.paths()
+	for _, p := range paths {
+		e := entries[p]
+		name := filepath.Join(dir, filepath.FromSlash(p))
+		if e.dir {
+			err = os.MkdirAll(name, 0700)
+		} else {
+			err = ioutil.WriteFile(name, []byte(e.content), e.mode)
+			if err == nil {
+				err = os.Chmod(name, e.mode)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Set directory modes last, they may restrict writing.
+	for i := len(paths) - 1; i >= 0; i-- {
+		e := entries[paths[i]]
+		if e.dir {
+			name := filepath.Join(dir, filepath.FromSlash(paths[i]))
+			if err = os.Chmod(name, e.mode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DiffFileTree compares the directory with the expected tree. The
+// result contains one line per missing, extra, or differing entry.
+// Modes are only compared if they are set in the expected tree.
+func DiffFileTree(dir string, expected FileTree) ([]string, error) {
+	ees, err := flattenFileTree(expected)
+	if err != nil {
+		return nil, err
+	}
+	obtained, err := ReadFileTree(dir)
+	if err != nil {
+		return nil, err
+	}
+	oes, err := flattenFileTree(obtained)
+	if err != nil {
+		return nil, err
+	}
+	diffs := []string{}
+	for _, p := range ees.paths() {
+		ee := ees[p]
+		oe, ok := oes[p]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("missing %s %s", ee.kind(), p))
+		case ee.dir != oe.dir:
+			diffs = append(diffs, fmt.Sprintf("%s is %s, expected %s", p, oe.kind(), ee.kind()))
+		default:
+			if ee.modeSet && ee.mode != oe.mode {
+				diffs = append(diffs, fmt.Sprintf("differing mode of %s %s: %v != %v", ee.kind(), p, oe.mode, ee.mode))
+			}
+			if !ee.dir && ee.content != oe.content {
+				diff := LineDiff(oe.content, ee.content)
+				diffs = append(diffs, fmt.Sprintf("differing content of file %s:\n%s", p, strings.TrimSuffix(diff, "\n")))
+			}
+		}
+	}
+	for _, p := range oes.paths() {
+		if _, ok := ees[p]; !ok {
+			diffs = append(diffs, fmt.Sprintf("extra %s %s", oes[p].kind(), p))
+		}
+	}
+	return diffs, nil
+}
+
+//--------------------
+// TEMPDIR FILE TREES
+//--------------------
+
+// Populate creates the directories and files of the tree
+// inside the temporary directory.
+func (td *TempDir) Populate(tree FileTree) {
+	if err := WriteFileTree(td.dir, tree); err != nil {
+		msg := fmt.Sprintf("cannot populate temporary directory %q: %v", td.dir, err)
+		td.assert.Fail(msg)
+	}
+}
+
+// EqualTree tests if the potentially nested directory inside the
+// temporary directory matches the expected tree. Missing, extra,
+// and differing entries are reported.
+func (td *TempDir) EqualTree(expected FileTree, name ...string) bool {
+	restore := td.assert.IncrCallstackOffset()
+	defer restore()
+	dir := filepath.Join(append([]string{td.dir}, name...)...)
+	diffs, err := DiffFileTree(dir, expected)
+	if err != nil {
+		msg := fmt.Sprintf("cannot compare directory %q: %v", dir, err)
+		return td.assert.Fail(msg)
+	}
+	if len(diffs) == 0 {
+		return true
+	}
+	info := fmt.Sprintf("directory %q differs from expected tree", dir)
+	return td.assert.Fail(append([]string{info}, diffs...)...)
+}
+
+//--------------------
+// FLAT ENTRIES
+//--------------------
+
+// flatEntry is a directory or file of a flattened tree.
+type flatEntry struct {
+	dir     bool
+	content string
+	mode    os.FileMode
+	modeSet bool
+}
+
+// kind returns the kind of the entry for messages.
+func (e *flatEntry) kind() string {
+	if e.dir {
+		return "dir"
+	}
+	return "file"
+}
+
+// flatEntries maps slash separated paths to entries.
+type flatEntries map[string]*flatEntry
+
+// paths returns the sorted paths of the entries.
+func (fes flatEntries) paths() []string {
+	paths := []string{}
+	for p := range fes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// flattenFileTree converts a tree into flat entries including
+// the implicit parent directories of paths.
+func flattenFileTree(tree FileTree) (flatEntries, error) {
+	fes := flatEntries{}
+	if err := fes.add("", tree); err != nil {
+		return nil, err
+	}
+	return fes, nil
+}
+
+// add adds the entries of the tree below the prefix.
+func (fes flatEntries) add(prefix string, tree FileTree) error {
+	for key, value := range tree {
+		p := path.Join(prefix, path.Clean("/" + key)[1:])
+		if p == prefix {
+			return fmt.Errorf("invalid name %q in file tree", key)
+		}
+		// Add implicit parent directories.
+		for parent := path.Dir(p); parent != "." && parent != prefix; parent = path.Dir(parent) {
+			if err := fes.set(parent, &flatEntry{dir: true, mode: 0700}); err != nil {
+				return err
+			}
+		}
+		var err error
+		switch v := value.(type) {
+		case string:
+			err = fes.set(p, &flatEntry{content: v, mode: 0600})
+		case []byte:
+			err = fes.set(p, &flatEntry{content: string(v), mode: 0600})
+		case File:
+			err = fes.set(p, &flatEntry{content: v.Content, mode: fileMode(v.Mode, 0600), modeSet: v.Mode != 0})
+		case FileTree:
+			if err = fes.set(p, &flatEntry{dir: true, mode: 0700}); err == nil {
+				err = fes.add(p, v)
+			}
+		case Dir:
+			if err = fes.set(p, &flatEntry{dir: true, mode: fileMode(v.Mode, 0700), modeSet: v.Mode != 0}); err == nil {
+				err = fes.add(p, v.Entries)
+			}
+		default:
+			err = fmt.Errorf("invalid entry %q in file tree: %s", key, ValueDescription(value))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// set sets an entry. Directories can be set multiple times, the
+// one with a mode wins.
+func (fes flatEntries) set(p string, e *flatEntry) error {
+	current, ok := fes[p]
+	switch {
+	case !ok:
+		fes[p] = e
+	case current.dir && e.dir:
+		if e.modeSet {
+			fes[p] = e
+		}
+	default:
+		return fmt.Errorf("duplicate entry %q in file tree", p)
+	}
+	return nil
+}
+
+// fileMode returns the permission bits of the mode or the
+// default if it's not set.
+func fileMode(mode, def os.FileMode) os.FileMode {
+	if mode == 0 {
+		return def
+	}
+	return mode.Perm()
+}
+
+// EOF
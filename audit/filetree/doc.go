@@ -0,0 +1,15 @@
+// Tideland Go Library - Audit - File Tree
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+// Package filetree of the Tideland Go Library reads the file trees
+// of the audit package described in SML and populates temporary
+// directories with them. It's an own package as it imports sml, so
+// the audit package stays without dependencies to the other packages
+// of the library.
+package filetree
+
+// EOF
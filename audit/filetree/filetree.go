@@ -0,0 +1,185 @@
+// Tideland Go Library - Audit - File Tree
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package filetree
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/sml"
+)
+
+//--------------------
+// SML FILE TREES
+//--------------------
+
+// ReadSML reads a file tree described in SML. The root tag
+// is "tree", directories and files are described by "dir" and
+// "file" tags containing "name" and optionally "mode" and, for
+// files, "content". Raw nodes keep the content as is, while
+// text nodes are trimmed.
+//
+//	{tree
+//	    {dir {name config} {mode 0755}
+//	        {file {name app.conf} {content {!key = value!}}}
+//	    }
+//	    {file {name README} {mode 0644} {content Hello, World!}}
+//	}
+func ReadSML(r io.Reader) (audit.FileTree, error) {
+	builder := &fileTreeBuilder{}
+	if err := sml.ReadSML(r, builder); err != nil {
+		return nil, err
+	}
+	return builder.tree, nil
+}
+
+// PopulateSML creates the directories and files of the tree
+// described in SML inside the temporary directory. Errors
+// are reported to the assertion.
+func PopulateSML(assert audit.Assertion, td *audit.TempDir, source string) {
+	tree, err := ReadSML(strings.NewReader(source))
+	if err != nil {
+		msg := fmt.Sprintf("cannot read file tree: %v", err)
+		assert.Fail(msg)
+		return
+	}
+	td.Populate(tree)
+}
+
+//--------------------
+// BUILDER
+//--------------------
+
+// fileTreeEntry is a directory or file while building
+// a file tree out of SML.
+type fileTreeEntry struct {
+	tag     string
+	name    string
+	mode    string
+	content string
+	tree    audit.FileTree
+}
+
+// fileTreeBuilder implements sml.Builder to read file trees.
+type fileTreeBuilder struct {
+	tags    []string
+	entries []*fileTreeEntry
+	tree    audit.FileTree
+}
+
+// BeginTagNode implements the sml.Builder interface.
+func (b *fileTreeBuilder) BeginTagNode(tag string) error {
+	switch {
+	case len(b.tags) == 0 && tag != "tree":
+		return fmt.Errorf("file tree has root %q, expected \"tree\"", tag)
+	case len(b.tags) == 0:
+		b.tree = audit.FileTree{}
+	case tag == "dir" || tag == "file":
+		if b.top() != "tree" && b.top() != "dir" {
+			return fmt.Errorf("%q not allowed inside %q", tag, b.top())
+		}
+		b.entries = append(b.entries, &fileTreeEntry{tag: tag, tree: audit.FileTree{}})
+	case tag == "name" || tag == "mode" || tag == "content":
+		if b.top() != "dir" && b.top() != "file" || tag == "content" && b.top() != "file" {
+			return fmt.Errorf("%q not allowed inside %q", tag, b.top())
+		}
+	default:
+		return fmt.Errorf("invalid tag %q in file tree", tag)
+	}
+	b.tags = append(b.tags, tag)
+	return nil
+}
+
+// EndTagNode implements the sml.Builder interface.
+func (b *fileTreeBuilder) EndTagNode() error {
+	tag := b.top()
+	b.tags = b.tags[:len(b.tags)-1]
+	if tag != "dir" && tag != "file" {
+		return nil
+	}
+	e := b.entries[len(b.entries)-1]
+	b.entries = b.entries[:len(b.entries)-1]
+	if e.name == "" {
+		return fmt.Errorf("%s without name in file tree", e.tag)
+	}
+	var mode os.FileMode
+	if e.mode != "" {
+		m, err := strconv.ParseUint(e.mode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid mode %q of %q in file tree", e.mode, e.name)
+		}
+		mode = os.FileMode(m)
+	}
+	parent := b.tree
+	if len(b.entries) > 0 {
+		parent = b.entries[len(b.entries)-1].tree
+	}
+	if _, ok := parent[e.name]; ok {
+		return fmt.Errorf("duplicate entry %q in file tree", e.name)
+	}
+	if e.tag == "dir" {
+		parent[e.name] = audit.Dir{Mode: mode, Entries: e.tree}
+	} else {
+		parent[e.name] = audit.File{Content: e.content, Mode: mode}
+	}
+	return nil
+}
+
+// TextNode implements the sml.Builder interface.
+func (b *fileTreeBuilder) TextNode(text string) error {
+	return b.value(strings.TrimSpace(text))
+}
+
+// RawNode implements the sml.Builder interface.
+func (b *fileTreeBuilder) RawNode(raw string) error {
+	return b.value(raw)
+}
+
+// CommentNode implements the sml.Builder interface.
+func (b *fileTreeBuilder) CommentNode(comment string) error {
+	return nil
+}
+
+// value sets the value of the current entry.
+func (b *fileTreeBuilder) value(value string) error {
+	if value == "" {
+		return nil
+	}
+	tag := b.top()
+	if tag != "name" && tag != "mode" && tag != "content" {
+		return fmt.Errorf("unexpected text %q in file tree", value)
+	}
+	e := b.entries[len(b.entries)-1]
+	switch tag {
+	case "name":
+		e.name += value
+	case "mode":
+		e.mode += value
+	default:
+		e.content += value
+	}
+	return nil
+}
+
+// top returns the current tag.
+func (b *fileTreeBuilder) top() string {
+	if len(b.tags) == 0 {
+		return ""
+	}
+	return b.tags[len(b.tags)-1]
+}
+
+// EOF
@@ -0,0 +1,71 @@
+// Tideland Go Library - Audit - File Tree - Unit Tests
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package filetree_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/audit/filetree"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestPopulateSML tests populating a temporary directory
+// described in SML.
+func TestPopulateSML(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+
+	filetree.PopulateSML(assert, td, `{tree
+		{dir {name config} {mode 0755}
+			{file {name app.conf} {content {!key = value
+!}}}
+		}
+		{file {name README} {mode 0644} {content Hello, World!}}
+		{dir {name empty}}
+	}`)
+
+	assert.True(td.EqualTree(audit.FileTree{
+		"config":          audit.Dir{Mode: 0755},
+		"config/app.conf": "key = value\n",
+		"README":          audit.File{Content: "Hello, World!", Mode: 0644},
+		"empty":           audit.FileTree{},
+	}))
+}
+
+// TestReadSMLErrors tests reading invalid SML file trees.
+func TestReadSMLErrors(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	tests := []struct {
+		source string
+		err    string
+	}{
+		{`{files}`, `file tree has root "files", expected "tree"`},
+		{`{tree {file {content foo}}}`, `file without name in file tree`},
+		{`{tree {dir {name a} {content foo}}}`, `"content" not allowed inside "dir"`},
+		{`{tree {file {name a} {mode 999}}}`, `invalid mode "999" of "a" in file tree`},
+		{`{tree {file {name a}} {dir {name a}}}`, `duplicate entry "a" in file tree`},
+		{`{tree {link {name a}}}`, `invalid tag "link" in file tree`},
+		{`{tree foo}`, `unexpected text "foo" in file tree`},
+	}
+	for _, test := range tests {
+		_, err := filetree.ReadSML(strings.NewReader(test.source))
+		assert.ErrorMatch(err, test.err, test.source)
+	}
+}
+
+// EOF
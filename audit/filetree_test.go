@@ -0,0 +1,122 @@
+// Tideland Go Library - Audit - Unit Tests
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tideland/golib/audit"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestTempDirPopulate tests populating a temporary directory.
+func TestTempDirPopulate(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+
+	td.Populate(audit.FileTree{
+		"config": audit.FileTree{
+			"app.conf": "key = value\n",
+		},
+		"data/index.html": audit.File{"<html></html>", 0644},
+		"logs":            audit.Dir{0755, nil},
+		"bin/tool":        []byte("#!/bin/sh\n"),
+	})
+
+	content, err := ioutil.ReadFile(filepath.Join(td.String(), "config", "app.conf"))
+	assert.Nil(err)
+	assert.Equal(string(content), "key = value\n")
+	fi, err := os.Stat(filepath.Join(td.String(), "data", "index.html"))
+	assert.Nil(err)
+	assert.Equal(fi.Mode().Perm(), os.FileMode(0644))
+	fi, err = os.Stat(filepath.Join(td.String(), "data"))
+	assert.Nil(err)
+	assert.True(fi.IsDir())
+	assert.Equal(fi.Mode().Perm(), os.FileMode(0700))
+	fi, err = os.Stat(filepath.Join(td.String(), "logs"))
+	assert.Nil(err)
+	assert.Equal(fi.Mode().Perm(), os.FileMode(0755))
+
+	tree, err := audit.ReadFileTree(td.String())
+	assert.Nil(err)
+	assert.Equal(tree["logs"], audit.Dir{0755, audit.FileTree{}})
+	assert.Equal(tree["bin"], audit.Dir{0700, audit.FileTree{
+		"tool": audit.File{"#!/bin/sh\n", 0600},
+	}})
+}
+
+// TestDiffFileTree tests comparing directories with trees.
+func TestDiffFileTree(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+
+	td.Populate(audit.FileTree{
+		"a.txt":     "one\ntwo\nthree\n",
+		"b.txt":     audit.File{"b", 0644},
+		"sub/c.txt": "c",
+		"sub/d":     audit.FileTree{},
+		"extra.txt": "extra",
+	})
+
+	diffs, err := audit.DiffFileTree(td.String(), audit.FileTree{
+		"a.txt":       "one\n2\nthree\n",
+		"b.txt":       audit.File{"b", 0600},
+		"sub/c.txt":   "c",
+		"sub/d":       "d",
+		"missing.txt": "missing",
+	})
+	assert.Nil(err)
+	assert.Equal(diffs, []string{
+		"differing content of file a.txt:\n     1: one\n-    2: 2\n+    2: two\n     3: three",
+		"differing mode of file b.txt: -rw-r--r-- != -rw-------",
+		"missing file missing.txt",
+		"sub/d is dir, expected file",
+		"extra file extra.txt",
+	})
+
+	_, err = audit.DiffFileTree(td.String(), audit.FileTree{"x": 42})
+	assert.ErrorMatch(err, `invalid entry "x" in file tree: .*`)
+	_, err = audit.DiffFileTree(filepath.Join(td.String(), "not-existing"), audit.FileTree{})
+	assert.ErrorMatch(err, `.* no such file or directory`)
+}
+
+// TestTempDirEqualTree tests the failing tree assertion.
+func TestTempDirEqualTree(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	validation, failures := audit.NewValidationAssertion()
+	td := audit.NewTempDir(validation)
+	defer td.Restore()
+
+	td.Populate(audit.FileTree{
+		"sub/a.txt": "a",
+	})
+
+	assert.True(td.EqualTree(audit.FileTree{"a.txt": "a"}, "sub"))
+	assert.False(td.EqualTree(audit.FileTree{"a.txt": "b"}, "sub"))
+	assert.Length(failures.Details(), 1)
+	msg := failures.Details()[0].Message()
+	assert.Substring("differs from expected tree", msg)
+	assert.Substring("differing content of file a.txt", msg)
+	fileName, _, funcName := failures.Details()[0].Location()
+	assert.Equal(fileName, "filetree_test.go")
+	assert.Equal(funcName, "TestTempDirEqualTree")
+}
+
+// EOF
@@ -0,0 +1,14 @@
+// Tideland Go Library - Audit - Fuzz
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+// Package fuzz of the Tideland Go Library helps to write native Go
+// fuzz tests. It seeds the corpus with data of the audit generator
+// and runs the fuzz targets with an audit assertion. It's an own
+// package as it imports testing, which the audit package doesn't.
+package fuzz
+
+// EOF
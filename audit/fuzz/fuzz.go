@@ -0,0 +1,159 @@
+// Tideland Go Library - Audit - Fuzz
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package fuzz
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+)
+
+//--------------------
+// CONSTANTS
+//--------------------
+
+// baseTime is the base for generated times.
+var baseTime = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+//--------------------
+// FUZZER
+//--------------------
+
+// Fuzzer bridges the native fuzzing of Go and the assertions and
+// generators of the audit package. It seeds the corpus with generated
+// data and runs the fuzz target with an assertion. Failing inputs
+// are logged in the format of the corpus files in testdata/fuzz,
+// so that they can be stored and replayed.
+//
+//     func FuzzParse(f *testing.F) {
+//         fz := fuzz.NewFuzzer(f, audit.NewGenerator(audit.FixedRand()))
+//         fz.AddWords(10)
+//         fz.AddJSON(10)
+//         fz.Fuzz(func(assert audit.Assertion, input string) {
+//             _, err := Parse(input)
+//             assert.Nil(err)
+//         })
+//     }
+type Fuzzer struct {
+	f   *testing.F
+	gen *audit.Generator
+}
+
+// NewFuzzer creates a fuzzer using the passed generator
+// for the seeds.
+func NewFuzzer(f *testing.F, gen *audit.Generator) *Fuzzer {
+	return &Fuzzer{
+		f:   f,
+		gen: gen,
+	}
+}
+
+// Add adds the inputs to the seed corpus.
+func (fz *Fuzzer) Add(inputs ...string) {
+	for _, input := range inputs {
+		fz.f.Add(input)
+	}
+}
+
+// AddWords adds count generated words to the seed corpus.
+func (fz *Fuzzer) AddWords(count int) {
+	fz.Add(fz.gen.Words(count)...)
+}
+
+// AddPatterns adds count strings generated by the pattern
+// to the seed corpus.
+func (fz *Fuzzer) AddPatterns(pattern string, count int) {
+	for i := 0; i < count; i++ {
+		fz.Add(fz.gen.Pattern(pattern))
+	}
+}
+
+// AddTimes adds count generated times formatted with the
+// layout to the seed corpus.
+func (fz *Fuzzer) AddTimes(layout string, count int) {
+	for i := 0; i < count; i++ {
+		t := fz.gen.Time(time.UTC, baseTime, 100*365*24*time.Hour)
+		fz.Add(t.Format(layout))
+	}
+}
+
+// AddJSON adds count generated JSON documents to the seed corpus.
+// Every second one is cut at a random position to get fragments.
+func (fz *Fuzzer) AddJSON(count int) {
+	for i := 0; i < count; i++ {
+		doc := fuzzJSON(fz.gen, 3)
+		if i%2 == 1 && len(doc) > 1 {
+			doc = doc[:fz.gen.Int(1, len(doc)-1)]
+		}
+		fz.Add(doc)
+	}
+}
+
+// Fuzz runs the target for the seed corpus, or for generated inputs
+// when fuzzing is activated. The target tests the input with the
+// passed assertion.
+func (fz *Fuzzer) Fuzz(target func(assert audit.Assertion, input string)) {
+	fz.f.Fuzz(func(t *testing.T, input string) {
+		defer func() {
+			if t.Failed() {
+				name := strings.SplitN(t.Name(), "/", 2)[0]
+				t.Logf("failing input %s, replay it by storing the following in testdata/fuzz/%s/<file>\n%s",
+					strconv.Quote(input), name, CorpusEntry(input))
+			}
+		}()
+		target(audit.NewTestingAssertion(t, true), input)
+	})
+}
+
+// CorpusEntry returns the input in the format of the
+// corpus files in testdata/fuzz/<FuzzTest>.
+func CorpusEntry(input string) string {
+	return fmt.Sprintf("go test fuzz v1\nstring(%s)\n", strconv.Quote(input))
+}
+
+// fuzzJSON generates a JSON value with the maximum depth.
+func fuzzJSON(g *audit.Generator, depth int) string {
+	kind := g.Int(0, 6)
+	if depth <= 0 && kind > 4 {
+		kind = g.Int(0, 4)
+	}
+	switch kind {
+	case 0:
+		return "null"
+	case 1:
+		return strconv.FormatBool(g.FlipCoin(50))
+	case 2:
+		return strconv.Itoa(g.Int(-1000, 1000))
+	case 3:
+		return strconv.FormatFloat(float64(g.Int(-100000, 100000))/100, 'f', -1, 64)
+	case 4:
+		return strconv.Quote(g.Sentence())
+	case 5:
+		values := make([]string, g.Int(0, 5))
+		for i := range values {
+			values[i] = fuzzJSON(g, depth-1)
+		}
+		return "[" + strings.Join(values, ",") + "]"
+	default:
+		fields := make([]string, g.Int(0, 5))
+		for i := range fields {
+			fields[i] = strconv.Quote(g.Word()) + ":" + fuzzJSON(g, depth-1)
+		}
+		return "{" + strings.Join(fields, ",") + "}"
+	}
+}
+
+// EOF
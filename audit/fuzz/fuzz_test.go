@@ -0,0 +1,69 @@
+// Tideland Go Library - Audit - Fuzz - Unit Tests
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package fuzz_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/audit/fuzz"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestFuzzCorpusEntry tests the replayable format of inputs.
+func TestFuzzCorpusEntry(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	assert.Equal(fuzz.CorpusEntry("foo"), "go test fuzz v1\nstring(\"foo\")\n")
+	assert.Equal(fuzz.CorpusEntry("a\n\"b\""), "go test fuzz v1\nstring(\"a\\n\\\"b\\\"\")\n")
+}
+
+// FuzzQuote tests the fuzzer with seeds of words, patterns, and times.
+func FuzzQuote(f *testing.F) {
+	fz := fuzz.NewFuzzer(f, audit.NewGenerator(audit.FixedRand()))
+	fz.Add("", "\x00")
+	fz.AddWords(10)
+	fz.AddPatterns("^A^a^0 ^h^h", 10)
+	fz.AddTimes(time.RFC3339, 10)
+	fz.Fuzz(func(assert audit.Assertion, input string) {
+		unquoted, err := strconv.Unquote(strconv.Quote(input))
+		assert.Nil(err)
+		assert.Equal(unquoted, input)
+	})
+}
+
+// FuzzJSON tests the fuzzer with seeds of JSON documents
+// and fragments.
+func FuzzJSON(f *testing.F) {
+	fz := fuzz.NewFuzzer(f, audit.NewGenerator(audit.FixedRand()))
+	fz.AddJSON(20)
+	fz.Fuzz(func(assert audit.Assertion, input string) {
+		var value interface{}
+		if err := json.Unmarshal([]byte(input), &value); err != nil {
+			assert.False(json.Valid([]byte(input)))
+			return
+		}
+		data, err := json.Marshal(value)
+		assert.Nil(err)
+		var again interface{}
+		assert.Nil(json.Unmarshal(data, &again))
+		assert.Equal(again, value)
+	})
+}
+
+// EOF
@@ -12,7 +12,12 @@ package audit
 //--------------------
 
 import (
+	"fmt"
+	"math"
+	"math/big"
 	"math/rand"
+	"reflect"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -74,7 +79,7 @@ func ToUpperFirst(s string) string {
 }
 
 // BuildEMail creates an e-mail address out of first and last
-// name and the domain.
+// name and the domain. Umlauts and accents are transliterated.
 func BuildEMail(first, last, domain string) string {
 	valid := make(map[rune]bool)
 	for _, r := range "abcdefghijklmnopqrstuvwxyz0123456789-" {
@@ -82,7 +87,7 @@ func BuildEMail(first, last, domain string) string {
 	}
 	name := func(in string) string {
 		out := []rune{}
-		for _, r := range strings.ToLower(in) {
+		for _, r := range ToASCII(strings.ToLower(in)) {
 			if valid[r] {
 				out = append(out, r)
 			}
@@ -92,6 +97,12 @@ func BuildEMail(first, last, domain string) string {
 	return name(first) + "." + name(last) + "@" + domain
 }
 
+// ToASCII transliterates the umlauts and accented characters
+// of the supported locales, e.g. for domains and e-mail addresses.
+func ToASCII(s string) string {
+	return asciiReplacer.Replace(s)
+}
+
 // BuildTime returns the current time plus or minus the passed
 // offset formatted as string and as Time. The returned time is
 // the parsed formatted one to avoid parsing troubles in tests.
@@ -112,13 +123,41 @@ func BuildTime(layout string, offset time.Duration) (string, time.Time) {
 // Generator is responsible for generating different random data
 // based on a random number generator.
 type Generator struct {
-	rand *rand.Rand
+	rand   *rand.Rand
+	locale *Locale
+}
+
+// GeneratorOption allows to configure a Generator.
+type GeneratorOption func(g *Generator)
+
+// UseLocale returns the option to set the locale of the generator.
+// Default is the EnglishLocale.
+//
+//	gen := audit.NewGenerator(audit.FixedRand(), audit.UseLocale(audit.GermanLocale))
+func UseLocale(locale *Locale) GeneratorOption {
+	return func(g *Generator) {
+		if locale != nil {
+			g.locale = locale
+		}
+	}
 }
 
 // NewGenerator returns a new generator using the passed random number
 // generator.
-func NewGenerator(rand *rand.Rand) *Generator {
-	return &Generator{rand}
+func NewGenerator(rand *rand.Rand, options ...GeneratorOption) *Generator {
+	g := &Generator{
+		rand:   rand,
+		locale: EnglishLocale,
+	}
+	for _, option := range options {
+		option(g)
+	}
+	return g
+}
+
+// Locale returns the locale used by the generator.
+func (g *Generator) Locale() *Locale {
+	return g.locale
 }
 
 // Int generates an int between lo and hi including
@@ -193,7 +232,7 @@ func (g *Generator) OneDurationOf(values ...time.Duration) time.Duration {
 
 // Word generates a random word.
 func (g *Generator) Word() string {
-	return g.OneStringOf(words...)
+	return g.OneStringOf(g.locale.Words...)
 }
 
 // Words generates a slice of random words
@@ -206,7 +245,8 @@ func (g *Generator) Words(count int) []string {
 }
 
 // LimitedWord generates a random word with a length between
-// lo and hi.
+// lo and hi. If the words of the locale contain none with the
+// chosen length the nearest one is taken.
 func (g *Generator) LimitedWord(lo, hi int) string {
 	length := g.Int(lo, hi)
 	if length < MinWordLen {
@@ -215,17 +255,30 @@ func (g *Generator) LimitedWord(lo, hi int) string {
 	if length > MaxWordLen {
 		length = MaxWordLen
 	}
+	words := g.locale.Words
+	wordsLen := len(words)
 	// Start anywhere in the list.
 	pos := g.Int(0, wordsLen)
-	for {
+	nearest := ""
+	distance := math.MaxInt32
+	for i := 0; i < wordsLen; i++ {
 		if pos >= wordsLen {
 			pos = 0
 		}
-		if len(words[pos]) == length {
+		d := utf8.RuneCountInString(words[pos]) - length
+		if d == 0 {
 			return words[pos]
 		}
+		if d < 0 {
+			d = -d
+		}
+		if d < distance {
+			nearest = words[pos]
+			distance = d
+		}
 		pos++
 	}
+	return nearest
 }
 
 // Pattern generates a string based on a pattern. Here different
@@ -305,45 +358,45 @@ func (g *Generator) Name() (first, middle, last string) {
 // MaleName generates a male name consisting out of first, middle
 // and last name.
 func (g *Generator) MaleName() (first, middle, last string) {
-	first = g.OneStringOf(maleFirstNames...)
-	middle = g.OneStringOf(maleFirstNames...)
+	first = g.OneStringOf(g.locale.MaleFirstNames...)
+	middle = g.OneStringOf(g.locale.MaleFirstNames...)
 	if g.FlipCoin(80) {
-		first += "-" + g.OneStringOf(maleFirstNames...)
+		first += "-" + g.OneStringOf(g.locale.MaleFirstNames...)
 	} else if g.FlipCoin(80) {
-		middle += "-" + g.OneStringOf(maleFirstNames...)
+		middle += "-" + g.OneStringOf(g.locale.MaleFirstNames...)
 	}
-	last = g.OneStringOf(lastNames...)
+	last = g.OneStringOf(g.locale.LastNames...)
 	return
 }
 
 // FemaleName generates a female name consisting out of first, middle
 // and last name.
 func (g *Generator) FemaleName() (first, middle, last string) {
-	first = g.OneStringOf(femaleFirstNames...)
-	middle = g.OneStringOf(femaleFirstNames...)
+	first = g.OneStringOf(g.locale.FemaleFirstNames...)
+	middle = g.OneStringOf(g.locale.FemaleFirstNames...)
 	if g.FlipCoin(80) {
-		first += "-" + g.OneStringOf(femaleFirstNames...)
+		first += "-" + g.OneStringOf(g.locale.FemaleFirstNames...)
 	} else if g.FlipCoin(80) {
-		middle += "-" + g.OneStringOf(femaleFirstNames...)
+		middle += "-" + g.OneStringOf(g.locale.FemaleFirstNames...)
 	}
-	last = g.OneStringOf(lastNames...)
+	last = g.OneStringOf(g.locale.LastNames...)
 	return
 }
 
 // Domain generates domain out of name and top level domain.
 func (g *Generator) Domain() string {
-	tld := g.OneStringOf(topLevelDomains...)
+	tld := g.OneStringOf(g.locale.TopLevelDomains...)
 	if g.FlipCoin(80) {
-		return g.LimitedWord(3, 5) + "-" + g.LimitedWord(3, 5) + "." + tld
+		return g.domainPart(3, 5) + "-" + g.domainPart(3, 5) + "." + tld
 	}
-	return g.LimitedWord(3, 10) + "." + tld
+	return g.domainPart(3, 10) + "." + tld
 }
 
 // URL generates a http, https or ftp URL, some of the leading
 // to a file.
 func (g *Generator) URL() string {
 	part := func() string {
-		return g.LimitedWord(2, 8)
+		return g.domainPart(2, 8)
 	}
 	start := g.OneStringOf("http://www.", "http://blog.", "https://www.", "ftp://")
 	ext := g.OneStringOf("html", "php", "jpg", "mp3", "txt")
@@ -368,6 +421,77 @@ func (g *Generator) EMail() string {
 	return BuildEMail(first, last, g.Domain())
 }
 
+// Street generates a street name with house number.
+func (g *Generator) Street() string {
+	street := g.OneStringOf(g.locale.Streets...)
+	number := strconv.Itoa(g.Int(1, 150))
+	// FlipCoin() is true if the percentage is reached,
+	// so 90 leads to about 10% suffixes.
+	if g.FlipCoin(90) {
+		number += g.Pattern("^a")
+	}
+	if g.locale.HouseNumberFirst {
+		return number + " " + street
+	}
+	return street + " " + number
+}
+
+// PostalCode generates a postal code.
+func (g *Generator) PostalCode() string {
+	return g.Pattern(g.locale.PostalCodePattern)
+}
+
+// City generates a city name.
+func (g *Generator) City() string {
+	return g.OneStringOf(g.locale.Cities...)
+}
+
+// Address generates a street address consisting out of
+// street, house number, postal code, and city.
+func (g *Generator) Address() string {
+	street := g.Street()
+	postalCode := g.PostalCode()
+	city := g.City()
+	if g.locale.PostalCodeFirst {
+		return street + ", " + postalCode + " " + city
+	}
+	return street + ", " + city + " " + postalCode
+}
+
+// Phone generates a phone number in international or
+// national notation.
+func (g *Generator) Phone() string {
+	return g.Pattern(g.OneStringOf(g.locale.PhonePatterns...))
+}
+
+// Company generates a company name based on last names.
+func (g *Generator) Company() string {
+	company := g.OneStringOf(g.locale.CompanyFormats...)
+	for strings.Contains(company, "%s") {
+		company = strings.Replace(company, "%s", g.OneStringOf(g.locale.LastNames...), 1)
+	}
+	return company
+}
+
+// IBAN generates an account number like an IBAN. It has the
+// country code and the length of the locale and valid check
+// digits, but the bank codes do not exist.
+func (g *Generator) IBAN() string {
+	bban := g.Pattern(g.locale.IBANPattern)
+	// Check digits based on ISO 7064 MOD 97-10.
+	digits := ""
+	for _, r := range bban + g.locale.IBANCountry + "00" {
+		if r >= 'A' && r <= 'Z' {
+			digits += strconv.Itoa(int(r-'A') + 10)
+		} else {
+			digits += string(r)
+		}
+	}
+	n, _ := new(big.Int).SetString(digits, 10)
+	check := 98 - new(big.Int).Mod(n, big.NewInt(97)).Int64()
+	return fmt.Sprintf("%s%02d%s", g.locale.IBANCountry, check, bban)
+}
+
 // Duration generates a duration between lo and hi including
 // those values.
 func (g *Generator) Duration(lo, hi time.Duration) time.Duration {
@@ -397,6 +521,296 @@ func (g *Generator) Time(loc *time.Location, base time.Time, dur time.Duration)
 	return base.Add(g.Duration(0, dur)).In(loc)
 }
 
+// Fill fills the exported fields of the struct the passed pointer
+// points to with random data. Nested structs, pointers, arrays,
+// slices, and maps are filled recursively, slices and maps with
+// one to five entries. Without a tag the generated value depends
+// on the type, strings get a word, numbers a value between 0 and
+// 1000. The generator can be chosen with the struct tag "audit",
+// in case of pointers, slices, arrays, and maps it is used for the
+// elements. Pointers, slices, and maps of a struct type which is
+// already filled stay nil, so recursive types don't lead to an
+// endless filling.
+//
+//     type Person struct {
+//         Name     string        `audit:"name"`
+//         EMail    *string       `audit:"email"`
+//         Age      int           `audit:"int,18,99"`
+//         Code     string        `audit:"pattern,^h^h-^A^A"`
+//         Tags     []string      `audit:"oneof,red,green,blue"`
+//         Timeout  time.Duration `audit:"duration,1s,1m"`
+//         Parent   *Person
+//         Internal string        `audit:"-"`
+//     }
+//
+// Known tags are "-" for skipping, "bool,percent" for FlipCoin(),
+// "int,lo,hi", "percent", "word", "word,lo,hi" for LimitedWord(),
+// "pattern,pattern", "oneof,value,...", "sentence", "paragraph",
+// "name", "firstname", "lastname", "domain", "url", "email",
+// "street", "postalcode", "city", "address", "phone", "company",
+// "iban", "duration,lo,hi", and "time,duration" for a time between the
+// 1st of January 2000 and that time plus the duration.
+func (g *Generator) Fill(ptr interface{}) error {
+	pv := reflect.ValueOf(ptr)
+	if pv.Kind() != reflect.Ptr || pv.IsNil() || pv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot fill %s, need a pointer to a struct", ValueDescription(ptr))
+	}
+	return g.fillValue(pv.Elem(), "", make(map[reflect.Type]bool))
+}
+
+// fillValue fills a value using an optional tag. The struct types
+// currently filled are marked in filling to stop recursions.
+func (g *Generator) fillValue(v reflect.Value, tag string, filling map[reflect.Type]bool) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		// The tag is used for the elements.
+		if filling[fillBaseType(v.Type())] {
+			// Recursive type, leave it nil.
+			return nil
+		}
+	case reflect.Array:
+		// The tag is used for the elements.
+	default:
+		if tag != "" {
+			return g.fillTagged(v, tag)
+		}
+	}
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		v.SetInt(int64(g.Duration(time.Second, time.Hour)))
+		return nil
+	case v.Type() == reflect.TypeOf(time.Time{}):
+		v.Set(reflect.ValueOf(g.Time(time.UTC, fillBaseTime, 10*365*24*time.Hour)))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(g.FlipCoin(50))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(g.Int(0, fillLimit(v.Type()))))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(uint64(g.Int(0, fillLimit(v.Type()))))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(g.rand.Float64() * 1000)
+	case reflect.String:
+		v.SetString(g.Word())
+	case reflect.Struct:
+		filling[v.Type()] = true
+		defer delete(filling, v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			ftag := field.Tag.Get("audit")
+			if field.PkgPath != "" || ftag == "-" {
+				// Unexported or skipped.
+				continue
+			}
+			if err := g.fillValue(v.Field(i), ftag, filling); err != nil {
+				return fmt.Errorf("field %s: %v", field.Name, err)
+			}
+		}
+	case reflect.Ptr:
+		ev := reflect.New(v.Type().Elem())
+		if err := g.fillValue(ev.Elem(), tag, filling); err != nil {
+			return err
+		}
+		v.Set(ev)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := g.fillValue(v.Index(i), tag, filling); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		length := g.Int(1, 5)
+		v.Set(reflect.MakeSlice(v.Type(), length, length))
+		for i := 0; i < length; i++ {
+			if err := g.fillValue(v.Index(i), tag, filling); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		length := g.Int(1, 5)
+		v.Set(reflect.MakeMap(v.Type()))
+		for i := 0; i < length; i++ {
+			kv := reflect.New(v.Type().Key()).Elem()
+			if err := g.fillValue(kv, "", filling); err != nil {
+				return err
+			}
+			ev := reflect.New(v.Type().Elem()).Elem()
+			if err := g.fillValue(ev, tag, filling); err != nil {
+				return err
+			}
+			v.SetMapIndex(kv, ev)
+		}
+	}
+	// Interfaces, channels, and functions are left as they are.
+	return nil
+}
+
+// fillBaseType returns the type pointers, slices, arrays,
+// and maps finally contain.
+func fillBaseType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// fillTagged fills a value based on the generator defined
+// by the tag.
+func (g *Generator) fillTagged(v reflect.Value, tag string) error {
+	var value interface{}
+	parts := strings.Split(tag, ",")
+	args := parts[1:]
+	switch parts[0] {
+	case "bool":
+		if len(args) != 1 {
+			return fmt.Errorf("tag %q needs percent", tag)
+		}
+		percent, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("tag %q has invalid percent: %v", tag, err)
+		}
+		value = g.FlipCoin(percent)
+	case "int":
+		if len(args) != 2 {
+			return fmt.Errorf("tag %q needs lo and hi", tag)
+		}
+		lo, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("tag %q has invalid lo: %v", tag, err)
+		}
+		hi, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("tag %q has invalid hi: %v", tag, err)
+		}
+		value = g.Int(lo, hi)
+	case "percent":
+		value = g.Percent()
+	case "word":
+		switch len(args) {
+		case 0:
+			value = g.Word()
+		case 2:
+			lo, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("tag %q has invalid lo: %v", tag, err)
+			}
+			hi, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("tag %q has invalid hi: %v", tag, err)
+			}
+			value = g.LimitedWord(lo, hi)
+		default:
+			return fmt.Errorf("tag %q needs none or lo and hi", tag)
+		}
+	case "pattern":
+		// Pattern may contain commas.
+		value = g.Pattern(strings.TrimPrefix(tag, "pattern,"))
+	case "oneof":
+		if len(args) == 0 {
+			return fmt.Errorf("tag %q needs values", tag)
+		}
+		value = g.OneStringOf(args...)
+	case "sentence":
+		value = g.Sentence()
+	case "paragraph":
+		value = g.Paragraph()
+	case "name":
+		first, middle, last := g.Name()
+		value = first + " " + middle + " " + last
+	case "firstname":
+		value, _, _ = g.Name()
+	case "lastname":
+		_, _, value = g.Name()
+	case "domain":
+		value = g.Domain()
+	case "url":
+		value = g.URL()
+	case "email":
+		value = g.EMail()
+	case "street":
+		value = g.Street()
+	case "postalcode":
+		value = g.PostalCode()
+	case "city":
+		value = g.City()
+	case "address":
+		value = g.Address()
+	case "phone":
+		value = g.Phone()
+	case "company":
+		value = g.Company()
+	case "iban":
+		value = g.IBAN()
+	case "duration":
+		if len(args) != 2 {
+			return fmt.Errorf("tag %q needs lo and hi", tag)
+		}
+		lo, err := time.ParseDuration(args[0])
+		if err != nil {
+			return fmt.Errorf("tag %q has invalid lo: %v", tag, err)
+		}
+		hi, err := time.ParseDuration(args[1])
+		if err != nil {
+			return fmt.Errorf("tag %q has invalid hi: %v", tag, err)
+		}
+		value = g.Duration(lo, hi)
+	case "time":
+		if len(args) != 1 {
+			return fmt.Errorf("tag %q needs duration", tag)
+		}
+		dur, err := time.ParseDuration(args[0])
+		if err != nil {
+			return fmt.Errorf("tag %q has invalid duration: %v", tag, err)
+		}
+		value = g.Time(time.UTC, fillBaseTime, dur)
+	default:
+		return fmt.Errorf("tag %q is unknown", tag)
+	}
+	return fillSet(v, value, tag)
+}
+
+// fillSet sets the generated value, converted if needed.
+func fillSet(v reflect.Value, value interface{}, tag string) error {
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.Type().AssignableTo(v.Type()):
+		v.Set(rv)
+	case v.Kind() == reflect.String:
+		v.SetString(fmt.Sprintf("%v", value))
+	case rv.Kind() != reflect.String && rv.Type().ConvertibleTo(v.Type()):
+		v.Set(rv.Convert(v.Type()))
+	default:
+		return fmt.Errorf("tag %q cannot be used for type %v", tag, v.Type())
+	}
+	return nil
+}
+
+// fillLimit returns the upper limit for generated
+// numbers of the given type.
+func fillLimit(t reflect.Type) int {
+	switch t.Kind() {
+	case reflect.Int8:
+		return math.MaxInt8
+	case reflect.Uint8:
+		return math.MaxUint8
+	}
+	return 1000
+}
+
+// domainPart generates a limited word usable in domains.
+func (g *Generator) domainPart(lo, hi int) string {
+	return strings.ToLower(ToASCII(g.LimitedWord(lo, hi)))
+}
+
+// fillBaseTime is the base for generated times.
+var fillBaseTime = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
 //--------------------
 // GENERATOR DATA
 //--------------------
@@ -452,9 +866,6 @@ var words = []string{
 	"zypres", "zyril",
 }
 
-// wordsLen is the length of the word list.
-var wordsLen = len(words)
-
 const (
 	// MinWordLen is the length of the shortest word.
 	MinWordLen = 1
@@ -590,6 +1001,16 @@ var lastNames = []string{
 	"Sexton", "Moon", "Hendricks", "Rangel",
 }
 
+// asciiReplacer transliterates umlauts and accented characters.
+var asciiReplacer = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"Ä", "Ae", "Ö", "Oe", "Ü", "Ue",
+	"à", "a", "â", "a", "ç", "c", "é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "ô", "o", "œ", "oe", "ù", "u", "û", "u", "ÿ", "y",
+	"À", "A", "Â", "A", "Ç", "C", "É", "E", "È", "E", "Ê", "E", "Î", "I",
+	"Ô", "O", "Œ", "Oe", "Ù", "U", "Û", "U",
+)
+
 // topLevelDomains is a number of existing top level domains.
 var topLevelDomains = []string{"asia", "at", "au", "biz", "ch", "cn", "com", "de", "es",
 	"eu", "fr", "gr", "guru", "info", "it", "mobi", "name", "net", "org", "pl", "ru",
@@ -13,6 +13,8 @@ package audit_test
 
 import (
 	"fmt"
+	"math/big"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -319,10 +321,201 @@ func TestTimes(t *testing.T) {
 	}
 }
 
+// TestStreetSuffix tests that only about every tenth house
+// number gets a letter suffix.
+func TestStreetSuffix(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	suffix := regexp.MustCompile(`\d[a-z]\b`)
+
+	for _, locale := range []*audit.Locale{audit.EnglishLocale, audit.GermanLocale} {
+		gen := audit.NewGenerator(audit.FixedRand(), audit.UseLocale(locale))
+		suffixes := 0
+		for i := 0; i < 1000; i++ {
+			if suffix.MatchString(gen.Street()) {
+				suffixes++
+			}
+		}
+		assert.Range(suffixes, 50, 150, locale.Name)
+	}
+}
+
+// TestLocales tests the generation of localized data.
+func TestLocales(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	tests := []struct {
+		locale  *audit.Locale
+		address string
+		phone   string
+		iban    string
+	}{
+		{audit.EnglishLocale, `^\d+[a-z]? .+, .+ [A-Z]{2}\d [1-9][A-Z]{2}$`, `^\+44 \d{4} \d{6}$`, `^GB\d{2}[A-Z]{4}\d{14}$`},
+		{audit.GermanLocale, `^.+ \d+[a-z]?, \d{5} .+$`, `^\+49 \d{3} \d{7,8}$`, `^DE\d{20}$`},
+		{audit.FrenchLocale, `^\d+[a-z]? .+, \d{5} .+$`, `^(\+33 \d|0\d)( \d{2}){4}$`, `^FR\d{12}[A-Z0-9]{11}\d{2}$`},
+	}
+	for _, test := range tests {
+		assert.Equal(audit.LookupLocale(test.locale.Name), test.locale)
+		gen := audit.NewGenerator(audit.FixedRand(), audit.UseLocale(test.locale))
+		assert.Equal(gen.Locale(), test.locale)
+
+		for i := 0; i < 1000; i++ {
+			assert.Contents(gen.Word(), test.locale.Words)
+			first, _, last := gen.Name()
+			assert.Contents(strings.Split(first, "-")[0], append(test.locale.MaleFirstNames, test.locale.FemaleFirstNames...))
+			assert.Contents(last, test.locale.LastNames)
+			assert.Match(gen.Domain(), `^[a-z0-9.-]+\.[a-z]{2,4}$`)
+			assert.Match(gen.EMail(), `^[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,4}$`)
+			assert.Match(gen.Address(), test.address)
+			assert.Match(gen.Phone(), test.phone)
+			assert.Substring(" ", gen.Company())
+			iban := gen.IBAN()
+			assert.Match(iban, test.iban)
+			assert.True(validIBAN(iban), iban)
+		}
+	}
+	assert.Nil(audit.LookupLocale("xx"))
+
+	// Same seed leads to same data.
+	genA := audit.NewGenerator(audit.FixedRand(), audit.UseLocale(audit.GermanLocale))
+	genB := audit.NewGenerator(audit.FixedRand(), audit.UseLocale(audit.GermanLocale))
+	for i := 0; i < 100; i++ {
+		assert.Equal(genA.Address(), genB.Address())
+		assert.Equal(genA.Paragraph(), genB.Paragraph())
+		assert.Equal(genA.LimitedWord(1, 20), genB.LimitedWord(1, 20))
+	}
+}
+
+// TestFill tests the filling of structs.
+func TestFill(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	gen := audit.NewGenerator(audit.FixedRand())
+	type Address struct {
+		Street string `audit:"word,5,10"`
+		Number uint8
+		Zip    string `audit:"pattern,^1^0^0^0^0"`
+	}
+	type Person struct {
+		Name      string        `audit:"name"`
+		EMail     string        `audit:"email"`
+		Age       int           `audit:"int,18,99"`
+		Code      string        `audit:"pattern,^h^h-^A^A,^0"`
+		Active    bool          `audit:"bool,20"`
+		Rating    float64       `audit:"int,1,5"`
+		Tags      []string      `audit:"oneof,red,green,blue"`
+		Timeout   time.Duration `audit:"duration,1s,1m"`
+		Birthday  time.Time     `audit:"time,8760h"`
+		Created   time.Time
+		Address   Address
+		Secondary *Address
+		Scores    map[string]int `audit:"percent"`
+		Skipped   string         `audit:"-"`
+		internal  string
+	}
+
+	for i := 0; i < 100; i++ {
+		var p Person
+		assert.Nil(gen.Fill(&p))
+		assert.Match(p.Name, `\S+ \S+ \S+`)
+		assert.Match(p.EMail, `\S+@\S+`)
+		assert.Range(p.Age, 18, 99)
+		assert.Match(p.Code, `[0-9a-f]{2}-[A-Z]{2},[0-9]`)
+		assert.Range(p.Rating, 1.0, 5.0)
+		assert.Range(len(p.Tags), 1, 5)
+		for _, tag := range p.Tags {
+			assert.Contents(tag, []string{"red", "green", "blue"})
+		}
+		assert.Range(p.Timeout, time.Second, time.Minute)
+		assert.Equal(p.Birthday.Year(), 2000)
+		assert.Range(p.Created.Year(), 2000, 2010)
+		assert.Range(len(p.Address.Street), 5, 10)
+		assert.Match(p.Address.Zip, `[1-9][0-9]{4}`)
+		assert.NotNil(p.Secondary)
+		assert.NotEmpty(p.Secondary.Street)
+		assert.Range(len(p.Scores), 1, 5)
+		for _, score := range p.Scores {
+			assert.Range(score, 0, 100)
+		}
+		assert.Empty(p.Skipped)
+		assert.Empty(p.internal)
+	}
+
+	// Check determinism.
+	var pa, pb Person
+	assert.Nil(audit.NewGenerator(audit.FixedRand()).Fill(&pa))
+	assert.Nil(audit.NewGenerator(audit.FixedRand()).Fill(&pb))
+	assert.Equal(pa, pb)
+}
+
+// TestFillRecursive tests the filling of recursive types
+// and tagged pointers.
+func TestFillRecursive(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	gen := audit.NewGenerator(audit.FixedRand())
+	type Node struct {
+		Name     string
+		EMail    *string `audit:"email"`
+		Next     *Node
+		Children []Node
+		Named    map[string]*Node
+		Inner    struct {
+			Parent *Node
+			Count  *int `audit:"int,1,9"`
+		}
+	}
+
+	var n Node
+	assert.Nil(gen.Fill(&n))
+	assert.NotEmpty(n.Name)
+	assert.NotNil(n.EMail)
+	assert.Match(*n.EMail, `\S+@\S+`)
+	assert.Nil(n.Next)
+	assert.Nil(n.Children)
+	assert.Nil(n.Named)
+	assert.Nil(n.Inner.Parent)
+	assert.NotNil(n.Inner.Count)
+	assert.Range(*n.Inner.Count, 1, 9)
+}
+
+// TestFillErrors tests the errors when filling structs.
+func TestFillErrors(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	gen := audit.NewGenerator(audit.FixedRand())
+
+	var s string
+	assert.ErrorMatch(gen.Fill(&s), "cannot fill ptr to string, need a pointer to a struct")
+	assert.ErrorMatch(gen.Fill(struct{}{}), "cannot fill struct , need a pointer to a struct")
+
+	var unknown struct {
+		Foo string `audit:"foo"`
+	}
+	assert.ErrorMatch(gen.Fill(&unknown), `field Foo: tag "foo" is unknown`)
+	var illegal struct {
+		Foo int `audit:"int,a,b"`
+	}
+	assert.ErrorMatch(gen.Fill(&illegal), `field Foo: tag "int,a,b" has invalid lo: .*`)
+	var mismatch struct {
+		Foo bool `audit:"email"`
+	}
+	assert.ErrorMatch(gen.Fill(&mismatch), `field Foo: tag "email" cannot be used for type bool`)
+}
+
 //--------------------
 // HELPER
 //--------------------
 
 var info = fmt.Sprintf
 
+// validIBAN checks the check digits of an IBAN.
+func validIBAN(iban string) bool {
+	digits := ""
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			digits += fmt.Sprintf("%d", int(r-'A')+10)
+		} else {
+			digits += string(r)
+		}
+	}
+	n, ok := new(big.Int).SetString(digits, 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
 // EOF
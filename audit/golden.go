@@ -0,0 +1,103 @@
+// Tideland Go Library - Audit
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+//--------------------
+// CONSTANTS
+//--------------------
+
+const (
+	// GoldenDir is the directory containing the golden files. It
+	// is relative to the package directory of the running test.
+	GoldenDir = "testdata"
+
+	// GoldenExt is the extension of the golden files.
+	GoldenExt = ".golden"
+
+	// GoldenUpdateEnv names the environment variable switching
+	// the Golden assertion into update mode. Here the obtained
+	// values are written into the golden files instead of
+	// being compared, e.g.
+	//
+	//     AUDIT_UPDATE_GOLDEN=true go test ./...
+	GoldenUpdateEnv = "AUDIT_UPDATE_GOLDEN"
+)
+
+//--------------------
+// GOLDEN FILES
+//--------------------
+
+// GoldenPath returns the path of the golden file with the
+// given name.
+func GoldenPath(name string) string {
+	return filepath.Join(GoldenDir, name+GoldenExt)
+}
+
+// UpdateGolden returns true if the golden files shall be
+// updated instead of being compared.
+func UpdateGolden() bool {
+	update, err := strconv.ParseBool(os.Getenv(GoldenUpdateEnv))
+	if err != nil {
+		return false
+	}
+	return update
+}
+
+// goldenDiff transports the expected golden file and the
+// difference to the obtained value.
+type goldenDiff struct {
+	path string
+	diff string
+}
+
+// goldenContent converts the obtained value into the
+// content of a golden file.
+func goldenContent(obtained interface{}) string {
+	switch o := obtained.(type) {
+	case string:
+		return o
+	case []byte:
+		return string(o)
+	case fmt.Stringer:
+		return o.String()
+	default:
+		return fmt.Sprintf("%v", obtained)
+	}
+}
+
+// readGolden reads the content of a golden file.
+func readGolden(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// writeGolden writes the content of a golden file and
+// creates the needed directories.
+func writeGolden(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
+
+// EOF
@@ -0,0 +1,72 @@
+// Tideland Go Library - Audit - Unit Tests
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"os"
+	"testing"
+
+	"github.com/tideland/golib/audit"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestAssertGolden tests the Golden() assertion.
+func TestAssertGolden(t *testing.T) {
+	successfulAssert := successfulAssertion(t)
+	failingAssert := failingAssertion(t)
+	text := "first line\nsecond line\nthird line\n"
+
+	successfulAssert.Golden(text, "golden-text", "should not fail")
+	successfulAssert.Golden([]byte(text), "golden-text", "should not fail")
+	failingAssert.Golden("first line\nthird line\nfourth line\n", "golden-text", "should fail and be logged")
+	failingAssert.Golden(text, "golden-not-existing", "should fail and be logged")
+}
+
+// TestValidationGolden tests the Golden() assertion with
+// a validation assertion.
+func TestValidationGolden(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	validation, failures := audit.NewValidationAssertion()
+
+	validation.Golden("first line\nchanged line\nthird line\n", "golden-text")
+	assert.Length(failures.Details(), 1)
+	assert.Equal(failures.Details()[0].Test(), audit.Golden)
+	assert.Substring("-    2: second line", failures.Error().Error())
+	assert.Substring("+    2: changed line", failures.Error().Error())
+}
+
+// TestGoldenUpdate tests the updating of golden files.
+func TestGoldenUpdate(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+	ev := audit.NewEnvVars(assert)
+	defer ev.Restore()
+	wd, err := os.Getwd()
+	assert.Nil(err)
+	assert.Nil(os.Chdir(td.String()))
+	defer os.Chdir(wd)
+
+	ev.Set(audit.GoldenUpdateEnv, "true")
+	assert.True(audit.UpdateGolden())
+	assert.Golden(4711, "golden-update")
+	assert.PathExists(audit.GoldenPath("golden-update"))
+
+	ev.Set(audit.GoldenUpdateEnv, "false")
+	assert.False(audit.UpdateGolden())
+	assert.Golden("4711", "golden-update")
+}
+
+// EOF
@@ -0,0 +1,157 @@
+// Tideland Go Library - Audit
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+//--------------------
+// GOROUTINES
+//--------------------
+
+// Goroutines helps to find goroutines leaking out of a test. It
+// takes a snapshot of the running goroutines when created and
+// compares it later to the then running ones.
+//
+//     assert := audit.NewTestingAssertion(t, true)
+//     gs := audit.NewGoroutines(assert)
+//     defer gs.NoLeaks(time.Second)
+//
+// Goroutines only consisting of runtime and testing frames are
+// ignored. Tests using it should not run in parallel to others.
+type Goroutines struct {
+	assert   Assertion
+	snapshot map[string]bool
+}
+
+// NewGoroutines creates a snapshot of the currently
+// running goroutines.
+func NewGoroutines(assert Assertion) *Goroutines {
+	gs := &Goroutines{
+		assert:   assert,
+		snapshot: make(map[string]bool),
+	}
+	for _, g := range goroutineStacks() {
+		gs.snapshot[g.id] = true
+	}
+	return gs
+}
+
+// Leaked returns the filtered stacks of the goroutines started
+// after the snapshot and still running.
+func (gs *Goroutines) Leaked() []string {
+	leaked := []string{}
+	for _, g := range goroutineStacks() {
+		if gs.snapshot[g.id] || len(g.frames) == 0 {
+			continue
+		}
+		leaked = append(leaked, g.String())
+	}
+	return leaked
+}
+
+// NoLeaks tests if all goroutines started after the snapshot
+// ended. They get the grace period to do so. Otherwise their
+// stacks are reported.
+func (gs *Goroutines) NoLeaks(grace time.Duration, msgs ...string) bool {
+	restore := gs.assert.IncrCallstackOffset()
+	defer restore()
+	timeout := time.Now().Add(grace)
+	for {
+		leaked := gs.Leaked()
+		if len(leaked) == 0 {
+			return true
+		}
+		if time.Now().After(timeout) {
+			info := fmt.Sprintf("%d goroutine(s) leaked after %v", len(leaked), grace)
+			return gs.assert.Fail(append(append([]string{info}, leaked...), msgs...)...)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+//--------------------
+// GOROUTINE STACKS
+//--------------------
+
+// goroutineStack contains the header and the
+// filtered frames of one goroutine.
+type goroutineStack struct {
+	id     string
+	header string
+	frames []string
+}
+
+// String returns the goroutine stack as string.
+func (g *goroutineStack) String() string {
+	return g.header + "\n" + strings.Join(g.frames, "\n")
+}
+
+// goroutineStacks returns the stacks of all goroutines
+// except the current one.
+func goroutineStacks() []*goroutineStack {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	gs := []*goroutineStack{}
+	// First one is the current goroutine.
+	for _, block := range strings.Split(string(buf), "\n\n")[1:] {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		fields := strings.Fields(lines[0])
+		if len(fields) < 2 || fields[0] != "goroutine" {
+			continue
+		}
+		g := &goroutineStack{
+			id:     fields[1],
+			header: lines[0],
+		}
+		// Frames consist of function and indented location line.
+		for i := 1; i < len(lines); i++ {
+			if strings.HasPrefix(lines[i], "\t") {
+				continue
+			}
+			function := strings.TrimPrefix(lines[i], "created by ")
+			if isIgnoredFrame(function) {
+				continue
+			}
+			g.frames = append(g.frames, lines[i])
+			if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+				g.frames = append(g.frames, lines[i+1])
+			}
+		}
+		gs = append(gs, g)
+	}
+	return gs
+}
+
+// isIgnoredFrame checks if a frame belongs to the runtime
+// or the testing package.
+func isIgnoredFrame(function string) bool {
+	for _, prefix := range []string{"runtime.", "testing.", "os/signal."} {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// EOF
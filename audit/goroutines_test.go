@@ -0,0 +1,72 @@
+// Tideland Go Library - Audit - Unit Tests
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestNoLeaks tests goroutines ending in time.
+func TestNoLeaks(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	gs := audit.NewGoroutines(assert)
+
+	assert.Empty(gs.Leaked())
+	for i := 0; i < 5; i++ {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+		}()
+	}
+	assert.Length(gs.Leaked(), 5)
+	assert.True(gs.NoLeaks(time.Second))
+}
+
+// TestLeaks tests the detection of leaking goroutines.
+func TestLeaks(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	validation, failures := audit.NewValidationAssertion()
+	gs := audit.NewGoroutines(validation)
+	stopC := make(chan struct{})
+
+	go leakingGoroutine(stopC)
+	assert.False(gs.NoLeaks(50*time.Millisecond, "should fail"))
+	assert.Length(failures.Details(), 1)
+	assert.Equal(failures.Details()[0].Test(), audit.Fail)
+	msg := failures.Details()[0].Message()
+	assert.Substring("1 goroutine(s) leaked after 50ms", msg)
+	assert.Substring("audit_test.leakingGoroutine", msg)
+	assert.Substring("created by github.com/tideland/golib/audit_test.TestLeaks", msg)
+	assert.False(strings.Contains(msg, "runtime."))
+	assert.Substring("should fail", msg)
+
+	close(stopC)
+	assert.True(gs.NoLeaks(time.Second))
+}
+
+//--------------------
+// HELPER
+//--------------------
+
+// leakingGoroutine waits until the channel is closed.
+func leakingGoroutine(stopC chan struct{}) {
+	<-stopC
+}
+
+// EOF
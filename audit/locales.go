@@ -0,0 +1,280 @@
+// Tideland Go Library - Audit
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit
+
+//--------------------
+// LOCALE
+//--------------------
+
+// Locale contains the data a Generator uses for the generation of
+// texts, names, addresses, and other localized data. The formats
+// for phones and account numbers are patterns like those of
+// Generator.Pattern(). Company formats contain "%s" as placeholder
+// for last names.
+type Locale struct {
+	Name              string
+	Words             []string
+	MaleFirstNames    []string
+	FemaleFirstNames  []string
+	LastNames         []string
+	TopLevelDomains   []string
+	Streets           []string
+	HouseNumberFirst  bool
+	Cities            []string
+	PostalCodePattern string
+	PostalCodeFirst   bool
+	PhonePatterns     []string
+	CompanyFormats    []string
+	IBANCountry       string
+	IBANPattern       string
+}
+
+// LookupLocale returns the predefined locale with the given
+// name. It's nil if no locale exists.
+func LookupLocale(name string) *Locale {
+	return locales[name]
+}
+
+// locales maps the names of the predefined locales.
+var locales = map[string]*Locale{
+	"de": GermanLocale,
+	"en": EnglishLocale,
+	"fr": FrenchLocale,
+}
+
+//--------------------
+// ENGLISH
+//--------------------
+
+// EnglishLocale is the default locale. It uses lorem ipsum
+// for texts and british addresses.
+var EnglishLocale = &Locale{
+	Name:             "en",
+	Words:            words,
+	MaleFirstNames:   maleFirstNames,
+	FemaleFirstNames: femaleFirstNames,
+	LastNames:        lastNames,
+	TopLevelDomains:  topLevelDomains,
+	Streets: []string{
+		"Alexandra Road", "Church Lane", "Church Road", "Church Street",
+		"Grange Road", "Green Lane", "High Street", "Highfield Road",
+		"Kings Road", "Kingsway", "London Road", "Main Street", "Manor Road",
+		"Mill Lane", "New Road", "Park Avenue", "Park Road", "Queens Road",
+		"Station Road", "The Avenue", "The Crescent", "Victoria Road",
+		"Windsor Road", "York Road",
+	},
+	HouseNumberFirst: true,
+	Cities: []string{
+		"Bath", "Belfast", "Birmingham", "Brighton", "Bristol", "Cambridge",
+		"Cardiff", "Edinburgh", "Exeter", "Glasgow", "Leeds", "Leicester",
+		"Liverpool", "London", "Manchester", "Newcastle", "Norwich",
+		"Nottingham", "Oxford", "Plymouth", "Reading", "Sheffield",
+		"Southampton", "York",
+	},
+	PostalCodePattern: "^A^A^1 ^1^A^A",
+	PostalCodeFirst:   false,
+	PhonePatterns: []string{
+		"+44 ^1^0^0^0 ^0^0^0^0^0^0",
+		"+44 7^0^0^0 ^0^0^0^0^0^0",
+	},
+	CompanyFormats: []string{
+		"%s Ltd", "%s PLC", "%s & Sons", "%s & %s Ltd", "%s Holdings", "%s Group",
+	},
+	IBANCountry: "GB",
+	IBANPattern: "^A^A^A^A^0^0^0^0^0^0^0^0^0^0^0^0^0^0",
+}
+
+//--------------------
+// GERMAN
+//--------------------
+
+// GermanLocale contains german texts, names, and addresses.
+var GermanLocale = &Locale{
+	Name: "de",
+	Words: []string{
+		"aber", "alle", "als", "also", "am", "an", "auch", "auf", "aus", "bei",
+		"bis", "da", "das", "dass", "dem", "den", "der", "des", "die", "doch",
+		"du", "ein", "eine", "einem", "einen", "er", "es", "für", "ganz", "gar",
+		"gern", "gut", "hat", "heute", "hier", "ich", "ihr", "im", "in", "ist",
+		"ja", "jetzt", "kann", "kein", "lange", "man", "mehr", "mit", "nach",
+		"nicht", "noch", "nun", "nur", "ob", "oder", "ohne", "schon", "sehr",
+		"sein", "sich", "sie", "sind", "so", "über", "um", "und", "uns", "unter",
+		"viel", "vom", "von", "vor", "war", "was", "weil", "wenn", "wer", "wie",
+		"wir", "wird", "wo", "zu", "zum", "zur", "zwischen",
+		"Abend", "Arbeit", "Bahnhof", "Baum", "Beispiel", "Berg", "Bild",
+		"Blume", "Brief", "Buch", "Dorf", "Eltern", "Ende", "Entwicklung",
+		"Fahrrad", "Fenster", "Frage", "Freund", "Frühstück", "Garten",
+		"Geschichte", "Geschwindigkeit", "Gesellschaft", "Haus", "Himmel",
+		"Hund", "Jahr", "Kind", "Kirche", "Land", "Leben", "Licht", "Mensch",
+		"Möglichkeit", "Morgen", "Musik", "Nachbarschaft", "Nacht", "Rathaus",
+		"Regen", "Schule", "Sommer", "Sonne", "Sprache", "Stadt", "Straße",
+		"Stunde", "Tag", "Tisch", "Tür", "Unternehmen", "Verantwortung",
+		"Wasser", "Weg", "Welt", "Wetter", "Winter", "Wissenschaft", "Woche",
+		"Wort", "Zeit", "Zeitung", "Zimmer",
+		"arbeiten", "bauen", "bleiben", "bringen", "denken", "essen", "fahren",
+		"finden", "fragen", "geben", "gehen", "glauben", "halten", "helfen",
+		"hören", "kaufen", "kommen", "lachen", "laufen", "leben", "lernen",
+		"lesen", "machen", "nehmen", "sagen", "schreiben", "sehen", "sitzen",
+		"spielen", "sprechen", "stehen", "suchen", "trinken", "warten",
+		"wissen", "wohnen", "zeigen",
+		"alt", "dunkel", "einfach", "freundlich", "fröhlich", "groß", "hell",
+		"kalt", "klein", "langsam", "neu", "ruhig", "schnell", "schön",
+		"schwierig", "selbstverständlich", "ungewöhnlich", "warm", "wichtig",
+		"zufrieden",
+	},
+	MaleFirstNames: []string{
+		"Andreas", "Christian", "Dieter", "Felix", "Finn", "Florian", "Frank",
+		"Günter", "Helmut", "Horst", "Jan", "Jonas", "Jürgen", "Klaus", "Leon",
+		"Lukas", "Markus", "Matthias", "Maximilian", "Michael", "Niklas", "Paul",
+		"Peter", "Sebastian", "Stefan", "Thomas", "Tim", "Tobias", "Uwe",
+		"Wolfgang",
+	},
+	FemaleFirstNames: []string{
+		"Andrea", "Anna", "Birgit", "Christina", "Clara", "Claudia", "Emma",
+		"Greta", "Hannah", "Ida", "Ingrid", "Jana", "Julia", "Katharina", "Laura",
+		"Lea", "Lena", "Maria", "Marie", "Mia", "Monika", "Nicole", "Petra",
+		"Renate", "Sabine", "Sandra", "Sophie", "Stefanie", "Susanne", "Ursula",
+	},
+	LastNames: []string{
+		"Albrecht", "Arnold", "Bauer", "Baumann", "Beck", "Becker", "Berger",
+		"Bergmann", "Böhm", "Brandt", "Braun", "Busch", "Dietrich", "Engel",
+		"Fischer", "Frank", "Franke", "Friedrich", "Fuchs", "Graf", "Groß",
+		"Günther", "Haas", "Hahn", "Hartmann", "Heinrich", "Herrmann", "Hoffmann",
+		"Hofmann", "Horn", "Huber", "Jäger", "Jung", "Kaiser", "Keller", "Klein",
+		"Koch", "Köhler", "König", "Krämer", "Kraus", "Krause", "Krüger", "Kuhn",
+		"Lang", "Lange", "Lehmann", "Lorenz", "Ludwig", "Maier", "Mayer", "Meier",
+		"Meyer", "Möller", "Müller", "Neumann", "Otto", "Peters", "Pfeiffer",
+		"Pohl", "Richter", "Roth", "Sauer", "Schäfer", "Schmid", "Schmidt",
+		"Schmitt", "Schmitz", "Schneider", "Scholz", "Schreiber", "Schröder",
+		"Schubert", "Schulte", "Schulz", "Schulze", "Schumacher", "Schuster",
+		"Schwarz", "Seidel", "Simon", "Sommer", "Stein", "Vogel", "Vogt", "Voigt",
+		"Wagner", "Walter", "Weber", "Weiß", "Werner", "Winkler", "Winter",
+		"Wolf", "Wolff", "Ziegler", "Zimmermann",
+	},
+	TopLevelDomains: []string{"at", "ch", "com", "de", "eu", "info", "net", "org"},
+	Streets: []string{
+		"Am Markt", "Amselweg", "Bahnhofstraße", "Beethovenstraße", "Bergstraße",
+		"Birkenweg", "Dorfstraße", "Friedrichstraße", "Gartenstraße",
+		"Goethestraße", "Hauptstraße", "Jahnstraße", "Kastanienallee",
+		"Kirchstraße", "Lessingstraße", "Lindenstraße", "Mühlenweg", "Poststraße",
+		"Ringstraße", "Rosenweg", "Schillerstraße", "Schulstraße", "Waldstraße",
+		"Wiesenweg",
+	},
+	HouseNumberFirst: false,
+	Cities: []string{
+		"Berlin", "Bremen", "Dortmund", "Dresden", "Düsseldorf", "Erfurt",
+		"Essen", "Frankfurt am Main", "Freiburg", "Hamburg", "Hannover",
+		"Kassel", "Kiel", "Köln", "Leipzig", "Lübeck", "Mainz", "München",
+		"Münster", "Nürnberg", "Oldenburg", "Potsdam", "Rostock", "Stuttgart",
+	},
+	PostalCodePattern: "^0^1^0^0^0",
+	PostalCodeFirst:   true,
+	PhonePatterns: []string{
+		"+49 ^1^0^0 ^0^0^0^0^0^0^0",
+		"+49 1^1^0 ^0^0^0^0^0^0^0^0",
+	},
+	CompanyFormats: []string{
+		"%s GmbH", "%s AG", "%s KG", "%s & %s GmbH", "%s GmbH & Co. KG", "%s & %s OHG",
+	},
+	IBANCountry: "DE",
+	IBANPattern: "^0^0^0^0^0^0^0^0^0^0^0^0^0^0^0^0^0^0",
+}
+
+//--------------------
+// FRENCH
+//--------------------
+
+// FrenchLocale contains french texts, names, and addresses.
+var FrenchLocale = &Locale{
+	Name: "fr",
+	Words: []string{
+		"à", "au", "aux", "avec", "ce", "ces", "dans", "de", "des", "du", "elle",
+		"en", "et", "il", "je", "la", "le", "les", "leur", "mais", "ne", "nous",
+		"on", "ou", "où", "par", "pas", "pour", "qui", "que", "sa", "se", "son",
+		"sur", "un", "une", "vous", "y", "très", "bien", "aussi", "encore",
+		"toujours", "jamais", "déjà", "ici", "maintenant", "demain", "hier",
+		"ami", "arbre", "bateau", "bibliothèque", "bureau", "chemin", "cheval",
+		"chose", "ciel", "coeur", "connaissance", "développement", "école",
+		"enfant", "environnement", "famille", "femme", "fenêtre", "fleur", "gare",
+		"gouvernement", "homme", "jardin", "jour", "journal", "livre", "maison",
+		"matin", "mer", "monde", "montagne", "musique", "nuit", "pain", "pays",
+		"pluie", "porte", "question", "renseignement", "rue", "saison", "soir",
+		"soleil", "table", "temps", "travail", "vie", "village", "ville",
+		"voiture", "voyage",
+		"aimer", "aller", "attendre", "chanter", "chercher", "connaître",
+		"courir", "croire", "danser", "devoir", "dire", "donner", "dormir",
+		"écrire", "entendre", "être", "faire", "finir", "jouer", "lire", "manger",
+		"mettre", "parler", "partir", "penser", "pouvoir", "prendre", "regarder",
+		"rester", "savoir", "sortir", "tenir", "trouver", "venir", "voir",
+		"vouloir",
+		"beau", "blanc", "bon", "chaud", "clair", "content", "doux",
+		"extraordinaire", "facile", "froid", "grand", "heureux", "jeune", "joli",
+		"long", "magnifique", "nouveau", "particulièrement", "petit", "rapide",
+		"tranquille",
+	},
+	MaleFirstNames: []string{
+		"Alain", "André", "Antoine", "Arthur", "Bernard", "Christophe", "Daniel",
+		"Émile", "François", "Gabriel", "Henri", "Hugo", "Jacques", "Jean",
+		"Julien", "Léo", "Louis", "Lucas", "Mathis", "Michel", "Nathan",
+		"Nicolas", "Olivier", "Patrick", "Paul", "Philippe", "Pierre", "Raphaël",
+		"Théo", "Thomas",
+	},
+	FemaleFirstNames: []string{
+		"Alice", "Amélie", "Anne", "Aurélie", "Camille", "Catherine", "Céline",
+		"Chloé", "Christine", "Claire", "Élodie", "Emma", "Françoise", "Hélène",
+		"Inès", "Isabelle", "Jade", "Julie", "Juliette", "Léa", "Lina", "Louise",
+		"Manon", "Margaux", "Marie", "Monique", "Nathalie", "Sophie", "Sylvie",
+		"Zoé",
+	},
+	LastNames: []string{
+		"André", "Arnaud", "Aubert", "Barbier", "Benoît", "Bernard", "Bertrand",
+		"Blanc", "Blanchard", "Bonnet", "Bourgeois", "Boyer", "Brun", "Brunet",
+		"Caron", "Chevalier", "Clément", "Colin", "David", "Denis", "Dubois",
+		"Dufour", "Dumas", "Dumont", "Dupont", "Durand", "Duval", "Fabre",
+		"Faure", "Fontaine", "Fournier", "François", "Gaillard", "Garnier",
+		"Gauthier", "Gautier", "Gérard", "Girard", "Giraud", "Guérin",
+		"Guillaume", "Henry", "Joly", "Lacroix", "Lambert", "Laurent", "Leclerc",
+		"Leclercq", "Lecomte", "Lefebvre", "Lefèvre", "Legrand", "Lemaire",
+		"Lemoine", "Leroux", "Leroy", "Lucas", "Marchand", "Martin", "Masson",
+		"Mathieu", "Mercier", "Meunier", "Michel", "Moreau", "Morel", "Morin",
+		"Nicolas", "Noël", "Olivier", "Perrin", "Petit", "Picard", "Renard",
+		"Renaud", "Richard", "Rivière", "Robert", "Robin", "Roche", "Roger",
+		"Rolland", "Roussel", "Rousseau", "Roux", "Roy", "Simon", "Thomas",
+		"Vidal", "Vincent",
+	},
+	TopLevelDomains: []string{"be", "ca", "ch", "com", "eu", "fr", "info", "net", "org"},
+	Streets: []string{
+		"allée des Tilleuls", "avenue des Champs-Élysées", "avenue Jean Jaurès",
+		"boulevard Gambetta", "boulevard Saint-Michel", "chemin des Vignes",
+		"impasse des Lilas", "place de la Mairie", "quai de la Loire",
+		"rue de la Gare", "rue de la Paix", "rue de la République",
+		"rue de l'Église", "rue des Écoles", "rue du Moulin", "rue Nationale",
+		"rue Pasteur", "rue Victor Hugo",
+	},
+	HouseNumberFirst: true,
+	Cities: []string{
+		"Amiens", "Angers", "Besançon", "Bordeaux", "Brest", "Caen", "Dijon",
+		"Grenoble", "Le Havre", "Lille", "Limoges", "Lyon", "Marseille", "Metz",
+		"Montpellier", "Nancy", "Nantes", "Nice", "Nîmes", "Orléans", "Paris",
+		"Perpignan", "Reims", "Rennes", "Rouen", "Saint-Étienne", "Strasbourg",
+		"Toulon", "Toulouse", "Tours",
+	},
+	PostalCodePattern: "^0^1^0^0^0",
+	PostalCodeFirst:   true,
+	PhonePatterns: []string{
+		"+33 ^1 ^0^0 ^0^0 ^0^0 ^0^0",
+		"0^1 ^0^0 ^0^0 ^0^0 ^0^0",
+	},
+	CompanyFormats: []string{
+		"%s SA", "%s SARL", "%s SAS", "%s et Fils", "%s et %s SARL", "%s et Cie",
+	},
+	IBANCountry: "FR",
+	IBANPattern: "^0^0^0^0^0^0^0^0^0^0^Z^Z^Z^Z^Z^Z^Z^Z^Z^Z^Z^0^0",
+}
+
+// EOF
@@ -0,0 +1,147 @@
+// Tideland Go Library - Audit
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+//--------------------
+// CONSTANTS
+//--------------------
+
+// markovMaxWords limits the length of generated sentences if
+// the corpus contains no sentence ends.
+const markovMaxWords = 100
+
+//--------------------
+// MARKOV CHAIN
+//--------------------
+
+// MarkovChain contains the word transitions of a text corpus. The
+// order is the number of preceding words determining the next one.
+// Generator.MarkovSentence() and Generator.MarkovParagraph() use
+// it to generate texts resembling the corpus.
+//
+//	chain := audit.NewMarkovChain(2)
+//	err := chain.Train(corpus)
+//	...
+//	gen := audit.NewGenerator(audit.FixedRand())
+//	text := gen.MarkovParagraph(chain)
+type MarkovChain struct {
+	order       int
+	transitions map[string][]string
+	starts      [][]string
+}
+
+// NewMarkovChain creates an empty chain of the given order.
+// Orders below 1 are set to 1.
+func NewMarkovChain(order int) *MarkovChain {
+	if order < 1 {
+		order = 1
+	}
+	return &MarkovChain{
+		order:       order,
+		transitions: make(map[string][]string),
+	}
+}
+
+// Order returns the order of the chain.
+func (m *MarkovChain) Order() int {
+	return m.order
+}
+
+// Train reads the words of the corpus and adds their transitions
+// to the chain. Words ending with a period, an exclamation mark, or
+// a question mark end a sentence. Train can be called multiple times
+// with different corpora.
+func (m *MarkovChain) Train(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	prefix := []string{}
+	sentence := []string{}
+	for scanner.Scan() {
+		word := scanner.Text()
+		if len(prefix) == m.order {
+			key := markovKey(prefix)
+			m.transitions[key] = append(m.transitions[key], word)
+			prefix = append(prefix[1:], word)
+		} else {
+			prefix = append(prefix, word)
+		}
+		// Collect the start of each sentence.
+		if len(sentence) < m.order {
+			sentence = append(sentence, word)
+			if len(sentence) == m.order {
+				m.starts = append(m.starts, sentence)
+			}
+		}
+		if isSentenceEnd(word) {
+			sentence = []string{}
+		}
+	}
+	return scanner.Err()
+}
+
+// markovKey creates the transition key of a prefix.
+func markovKey(prefix []string) string {
+	return strings.Join(prefix, "\x00")
+}
+
+// isSentenceEnd checks if the word ends a sentence.
+func isSentenceEnd(word string) bool {
+	return strings.HasSuffix(word, ".") ||
+		strings.HasSuffix(word, "!") ||
+		strings.HasSuffix(word, "?")
+}
+
+//--------------------
+// GENERATOR
+//--------------------
+
+// MarkovSentence generates a sentence based on the passed chain. It
+// is empty if the chain contains no sentence with at least as many
+// words as its order.
+func (g *Generator) MarkovSentence(m *MarkovChain) string {
+	if len(m.starts) == 0 {
+		return ""
+	}
+	start := m.starts[g.Int(0, len(m.starts)-1)]
+	words := append([]string{}, start...)
+	for !isSentenceEnd(words[len(words)-1]) && len(words) < markovMaxWords {
+		nexts := m.transitions[markovKey(words[len(words)-m.order:])]
+		if len(nexts) == 0 {
+			break
+		}
+		words = append(words, g.OneStringOf(nexts...))
+	}
+	sentence := ToUpperFirst(strings.Join(words, " "))
+	if !isSentenceEnd(sentence) {
+		sentence = strings.TrimRight(sentence, ",;:") + "."
+	}
+	return sentence
+}
+
+// MarkovParagraph generates a paragraph between 2 and 10 sentences
+// based on the passed chain.
+func (g *Generator) MarkovParagraph(m *MarkovChain) string {
+	count := g.Int(2, 10)
+	sentences := make([]string, count)
+	for i := 0; i < count; i++ {
+		sentences[i] = g.MarkovSentence(m)
+	}
+	return strings.Join(sentences, " ")
+}
+
+// EOF
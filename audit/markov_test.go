@@ -0,0 +1,90 @@
+// Tideland Go Library - Audit - Unit Tests
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tideland/golib/audit"
+)
+
+//--------------------
+// CONSTANTS
+//--------------------
+
+// corpus is a simple text corpus for the tests.
+const corpus = `The cache loads the file. The cache stores the file
+for a while. The loader reads the file from the disk. Does the
+loader also watch the directory? The scroller reads the file too!`
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestMarkovSentence tests the generation of sentences.
+func TestMarkovSentence(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	gen := audit.NewGenerator(audit.FixedRand())
+	words := map[string]bool{}
+	for _, word := range strings.Fields(corpus) {
+		words[strings.ToLower(word)] = true
+	}
+
+	for order := 1; order <= 3; order++ {
+		chain := audit.NewMarkovChain(order)
+		assert.Equal(chain.Order(), order)
+		assert.Nil(chain.Train(strings.NewReader(corpus)))
+		for i := 0; i < 1000; i++ {
+			sentence := gen.MarkovSentence(chain)
+			assert.Match(sentence, `^[A-Z].*[.!?]$`)
+			for _, word := range strings.Fields(sentence) {
+				assert.True(words[strings.ToLower(word)], word)
+			}
+		}
+	}
+
+	// Order 4 keeps the only sentence as it is.
+	chain := audit.NewMarkovChain(4)
+	assert.Nil(chain.Train(strings.NewReader("The cache loads the file.")))
+	assert.Equal(gen.MarkovSentence(chain), "The cache loads the file.")
+
+	// Too short and empty corpora.
+	chain = audit.NewMarkovChain(3)
+	assert.Nil(chain.Train(strings.NewReader("Hello world.")))
+	assert.Equal(gen.MarkovSentence(chain), "")
+	chain = audit.NewMarkovChain(0)
+	assert.Equal(chain.Order(), 1)
+	assert.Equal(gen.MarkovSentence(chain), "")
+
+	// Missing sentence end is added.
+	chain = audit.NewMarkovChain(1)
+	assert.Nil(chain.Train(strings.NewReader("one two three")))
+	assert.Equal(gen.MarkovSentence(chain), "One two three.")
+}
+
+// TestMarkovParagraph tests the generation of paragraphs.
+func TestMarkovParagraph(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	chain := audit.NewMarkovChain(2)
+	assert.Nil(chain.Train(strings.NewReader(corpus)))
+	genA := audit.NewGenerator(audit.FixedRand())
+	genB := audit.NewGenerator(audit.FixedRand())
+
+	for i := 0; i < 100; i++ {
+		paragraph := genA.MarkovParagraph(chain)
+		assert.Equal(paragraph, genB.MarkovParagraph(chain))
+		assert.Match(paragraph, `^([A-Z][^.!?]*[.!?] ?){2,10}$`)
+	}
+}
+
+// EOF
@@ -0,0 +1,237 @@
+// Tideland Go Library - Audit
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+//--------------------
+// CALL
+//--------------------
+
+// Call is one recorded method call of a mock.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// String returns the call in Go notation.
+func (c *Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = fmt.Sprintf("%#v", arg)
+	}
+	return fmt.Sprintf("%s(%s)", c.Method, strings.Join(args, ", "))
+}
+
+//--------------------
+// RESULTS
+//--------------------
+
+// Results contains the programmed return values of a call.
+// The typed accessors return the zero value if the index is
+// out of range or the value is nil.
+type Results []interface{}
+
+// Get returns the result at the index.
+func (r Results) Get(i int) interface{} {
+	if i < 0 || i >= len(r) {
+		return nil
+	}
+	return r[i]
+}
+
+// Bool returns the result at the index as bool.
+func (r Results) Bool(i int) bool {
+	b, _ := r.Get(i).(bool)
+	return b
+}
+
+// Int returns the result at the index as int.
+func (r Results) Int(i int) int {
+	n, _ := r.Get(i).(int)
+	return n
+}
+
+// String returns the result at the index as string.
+func (r Results) String(i int) string {
+	s, _ := r.Get(i).(string)
+	return s
+}
+
+// Duration returns the result at the index as duration.
+func (r Results) Duration(i int) time.Duration {
+	d, _ := r.Get(i).(time.Duration)
+	return d
+}
+
+// Error returns the result at the index as error.
+func (r Results) Error(i int) error {
+	err, _ := r.Get(i).(error)
+	return err
+}
+
+//--------------------
+// MOCK
+//--------------------
+
+// Mock records the method calls of hand-written fakes and returns
+// programmed results. Fakes embed or reference it and record each
+// call, e.g.
+//
+//     type fakeJob struct {
+//         *audit.Mock
+//     }
+//
+//     func (j *fakeJob) ShallExecute(t time.Time) bool {
+//         return j.Record("ShallExecute", t).Bool(0)
+//     }
+//
+// Tests program the results and verify the calls afterwards.
+//
+//     job := &fakeJob{audit.NewMock(assert)}
+//     job.Returns("ShallExecute", false).Returns("ShallExecute", true)
+//     ...
+//     job.CalledTimes("ShallExecute", 2)
+type Mock struct {
+	mux     sync.Mutex
+	assert  Assertion
+	calls   []*Call
+	results map[string][]Results
+	counts  map[string]int
+}
+
+// NewMock creates a mock reporting failed verifications
+// to the passed assertion.
+func NewMock(assert Assertion) *Mock {
+	return &Mock{
+		assert:  assert,
+		results: make(map[string][]Results),
+		counts:  make(map[string]int),
+	}
+}
+
+// Returns programs the results of the next call of the method.
+// Multiple calls program a sequence, the last results are
+// repeated when the sequence is exhausted.
+func (m *Mock) Returns(method string, results ...interface{}) *Mock {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.results[method] = append(m.results[method], Results(results))
+	return m
+}
+
+// Record records a call of the method with its arguments and
+// returns the programmed results. Without any programmed
+// results they are empty.
+func (m *Mock) Record(method string, args ...interface{}) Results {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.calls = append(m.calls, &Call{method, args})
+	n := m.counts[method]
+	m.counts[method]++
+	sequence := m.results[method]
+	switch {
+	case len(sequence) == 0:
+		return Results{}
+	case n < len(sequence):
+		return sequence[n]
+	default:
+		return sequence[len(sequence)-1]
+	}
+}
+
+// Calls returns the recorded calls of the method. An empty
+// method name returns all calls.
+func (m *Mock) Calls(method string) []*Call {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	calls := []*Call{}
+	for _, call := range m.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset clears the recorded calls and the programmed results.
+func (m *Mock) Reset() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.calls = nil
+	m.results = make(map[string][]Results)
+	m.counts = make(map[string]int)
+}
+
+// CalledTimes tests if the method has been called the
+// given number of times.
+func (m *Mock) CalledTimes(method string, times int, msgs ...string) bool {
+	restore := m.assert.IncrCallstackOffset()
+	defer restore()
+	calls := m.Calls(method)
+	if len(calls) == times {
+		return true
+	}
+	info := fmt.Sprintf("%s called %d time(s), expected %d", method, len(calls), times)
+	return m.assert.Fail(append([]string{info}, msgs...)...)
+}
+
+// CalledWith tests if the method has been called at least
+// once with the given arguments.
+func (m *Mock) CalledWith(method string, args []interface{}, msgs ...string) bool {
+	restore := m.assert.IncrCallstackOffset()
+	defer restore()
+	calls := m.Calls(method)
+	for _, call := range calls {
+		if len(call.Args) == 0 && len(args) == 0 || reflect.DeepEqual(call.Args, args) {
+			return true
+		}
+	}
+	expected := &Call{method, args}
+	info := []string{fmt.Sprintf("%s not called", expected)}
+	for _, call := range calls {
+		info = append(info, fmt.Sprintf("recorded: %s", call))
+	}
+	return m.assert.Fail(append(info, msgs...)...)
+}
+
+// CalledInOrder tests if the methods have been called in the
+// given order. Other calls in between are allowed.
+func (m *Mock) CalledInOrder(methods []string, msgs ...string) bool {
+	restore := m.assert.IncrCallstackOffset()
+	defer restore()
+	calls := m.Calls("")
+	i := 0
+	for _, call := range calls {
+		if i < len(methods) && call.Method == methods[i] {
+			i++
+		}
+	}
+	if i == len(methods) {
+		return true
+	}
+	recorded := make([]string, len(calls))
+	for j, call := range calls {
+		recorded[j] = call.Method
+	}
+	info := fmt.Sprintf("calls not in order %s, recorded %s",
+		strings.Join(methods, ", "), strings.Join(recorded, ", "))
+	return m.assert.Fail(append([]string{info}, msgs...)...)
+}
+
+// EOF
@@ -0,0 +1,121 @@
+// Tideland Go Library - Audit - Unit Tests
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tideland/golib/audit"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestMockResults tests the programmed results of a mock.
+func TestMockResults(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	store := &fakeStore{audit.NewMock(assert)}
+
+	store.Returns("Load", "a", nil).
+		Returns("Load", "", errors.New("ouch")).
+		Returns("Size", 42)
+
+	value, err := store.Load("x")
+	assert.Equal(value, "a")
+	assert.Nil(err)
+	value, err = store.Load("y")
+	assert.Equal(value, "")
+	assert.ErrorMatch(err, "ouch")
+	// Last results are repeated.
+	_, err = store.Load("z")
+	assert.ErrorMatch(err, "ouch")
+	assert.Equal(store.Size(), 42)
+	assert.Equal(store.Size(), 42)
+	// Unprogrammed methods return zero values.
+	assert.Nil(store.Store("x", "b"))
+
+	assert.Length(store.Calls("Load"), 3)
+	assert.Length(store.Calls(""), 6)
+	assert.Equal(store.Calls("Store")[0].String(), `Store("x", "b")`)
+
+	store.Reset()
+	assert.Empty(store.Calls(""))
+	assert.Equal(store.Size(), 0)
+}
+
+// TestMockVerifications tests the verification of recorded calls.
+func TestMockVerifications(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	store := &fakeStore{audit.NewMock(assert)}
+
+	store.Load("a")
+	store.Store("a", "1")
+	store.Size()
+	store.Store("b", "2")
+
+	assert.True(store.CalledTimes("Store", 2))
+	assert.True(store.CalledTimes("Remove", 0))
+	assert.True(store.CalledWith("Store", []interface{}{"b", "2"}))
+	assert.True(store.CalledWith("Size", nil))
+	assert.True(store.CalledInOrder([]string{"Load", "Store", "Store"}))
+	assert.True(store.CalledInOrder([]string{"Size", "Store"}))
+}
+
+// TestMockFailures tests failing verifications of recorded calls.
+func TestMockFailures(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	validation, failures := audit.NewValidationAssertion()
+	store := &fakeStore{audit.NewMock(validation)}
+
+	store.Load("a")
+	store.Store("a", "1")
+
+	assert.False(store.CalledTimes("Load", 2, "should fail"))
+	assert.False(store.CalledWith("Store", []interface{}{"a", "2"}, "should fail"))
+	assert.False(store.CalledInOrder([]string{"Store", "Load"}, "should fail"))
+
+	details := failures.Details()
+	assert.Length(details, 3)
+	assert.Substring("Load called 1 time(s), expected 2 should fail", details[0].Message())
+	assert.Substring(`Store("a", "2") not called`, details[1].Message())
+	assert.Substring(`recorded: Store("a", "1") should fail`, details[1].Message())
+	assert.Substring("calls not in order Store, Load, recorded Load, Store should fail", details[2].Message())
+	fileName, _, funcName := details[0].Location()
+	assert.Equal(fileName, "mock_test.go")
+	assert.Equal(funcName, "TestMockFailures")
+}
+
+//--------------------
+// HELPER
+//--------------------
+
+// fakeStore is a simple fake using the mock.
+type fakeStore struct {
+	*audit.Mock
+}
+
+func (s *fakeStore) Load(key string) (string, error) {
+	results := s.Record("Load", key)
+	return results.String(0), results.Error(1)
+}
+
+func (s *fakeStore) Store(key, value string) error {
+	return s.Record("Store", key, value).Error(0)
+}
+
+func (s *fakeStore) Size() int {
+	return s.Record("Size").Int(0)
+}
+
+// EOF
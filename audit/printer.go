@@ -47,6 +47,9 @@ var testNames = []string{
 	Wait:         "wait",
 	Retry:        "retry",
 	Fail:         "fail",
+	Golden:       "golden",
+	Eventually:   "eventually",
+	Consistently: "consistently",
 }
 
 func (t Test) String() string {
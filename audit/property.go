@@ -0,0 +1,492 @@
+// Tideland Go Library - Audit
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//--------------------
+// CONSTANTS
+//--------------------
+
+const (
+	// PropertySeedEnv names the environment variable which can be
+	// used to set the seed of property checks to reproduce a failure.
+	PropertySeedEnv = "AUDIT_PROPERTY_SEED"
+
+	// maxPropertySize is the maximum size of generated values, e.g.
+	// the length of strings and slices.
+	maxPropertySize = 100
+
+	// maxShrinks is the maximum number of property calls during
+	// the shrinking of a failing input.
+	maxShrinks = 1000
+)
+
+var (
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+//--------------------
+// PROPERTY CHECKER
+//--------------------
+
+// PropertyChecker runs properties with generated arguments. A
+// property is a function with any number of arguments returning a
+// bool or an error. It fails if it returns false, a non-nil error, or
+// panics. Arguments are generated for bools, numbers, strings,
+// durations, times, as well as arrays, slices, maps, and pointers of
+// those.
+//
+//     assert := audit.NewTestingAssertion(t, true)
+//     pc := audit.NewPropertyChecker(assert, 100)
+//
+//     pc.Check(func(s string) bool {
+//         return reverse(reverse(s)) == s
+//     }, "double reverse is identity")
+//
+// In case of a failure the arguments are shrunk to smaller ints and
+// shorter strings, slices, and maps still failing. The failure
+// is reported with the seed. Setting the environment variable
+// AUDIT_PROPERTY_SEED to it reproduces the run.
+type PropertyChecker struct {
+	assert Assertion
+	runs   int
+	seed   int64
+}
+
+// NewPropertyChecker creates a property checker calling each
+// property the number of runs. The seed is taken from the
+// environment variable AUDIT_PROPERTY_SEED or the current time.
+func NewPropertyChecker(assert Assertion, runs int) *PropertyChecker {
+	if runs < 1 {
+		runs = 1
+	}
+	seed, err := strconv.ParseInt(os.Getenv(PropertySeedEnv), 10, 64)
+	if err != nil {
+		seed = time.Now().UnixNano()
+	}
+	return &PropertyChecker{
+		assert: assert,
+		runs:   runs,
+		seed:   seed,
+	}
+}
+
+// SetSeed sets the seed for the generating of arguments.
+func (pc *PropertyChecker) SetSeed(seed int64) {
+	pc.seed = seed
+}
+
+// Seed returns the seed for the generating of arguments.
+func (pc *PropertyChecker) Seed() int64 {
+	return pc.seed
+}
+
+// Check runs the property with generated arguments. Failures
+// are reported with the original and the shrunk arguments.
+func (pc *PropertyChecker) Check(property interface{}, msgs ...string) bool {
+	restore := pc.assert.IncrCallstackOffset()
+	defer restore()
+	pv, err := checkProperty(property)
+	if err != nil {
+		return pc.assert.Fail(append([]string{err.Error()}, msgs...)...)
+	}
+	g := NewGenerator(rand.New(rand.NewSource(pc.seed)))
+	for run := 0; run < pc.runs; run++ {
+		size := 1 + run*maxPropertySize/pc.runs
+		args := make([]reflect.Value, pv.Type().NumIn())
+		for i := range args {
+			arg, err := generateValue(g, pv.Type().In(i), size)
+			if err != nil {
+				return pc.assert.Fail(append([]string{err.Error()}, msgs...)...)
+			}
+			args[i] = arg
+		}
+		if err := callProperty(pv, args); err != nil {
+			shrunk, shrunkErr := shrinkArgs(pv, args, err)
+			info := []string{
+				fmt.Sprintf("property failed after %d run(s) with seed %d", run+1, pc.seed),
+				fmt.Sprintf("arguments: %s", argsString(args)),
+				fmt.Sprintf("shrunk:    %s", argsString(shrunk)),
+				fmt.Sprintf("error:     %v", shrunkErr),
+			}
+			return pc.assert.Fail(append(info, msgs...)...)
+		}
+	}
+	return true
+}
+
+//--------------------
+// PROPERTY HELPERS
+//--------------------
+
+// checkProperty checks if the passed value is a valid property.
+func checkProperty(property interface{}) (reflect.Value, error) {
+	pv := reflect.ValueOf(property)
+	if pv.Kind() != reflect.Func || pv.IsNil() {
+		return pv, fmt.Errorf("property is no function: %s", ValueDescription(property))
+	}
+	pt := pv.Type()
+	if pt.IsVariadic() {
+		return pv, fmt.Errorf("property must not be variadic")
+	}
+	if pt.NumOut() != 1 || (pt.Out(0).Kind() != reflect.Bool && pt.Out(0) != errorType) {
+		return pv, fmt.Errorf("property has to return a bool or an error")
+	}
+	return pv, nil
+}
+
+// callProperty calls the property with the arguments and returns
+// an error if it fails.
+func callProperty(pv reflect.Value, args []reflect.Value) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("property panicked: %v", r)
+		}
+	}()
+	out := pv.Call(args)[0]
+	if out.Kind() == reflect.Bool {
+		if !out.Bool() {
+			return fmt.Errorf("property returned false")
+		}
+		return nil
+	}
+	if !out.IsNil() {
+		return out.Interface().(error)
+	}
+	return nil
+}
+
+// argsString returns the arguments in a readable form.
+func argsString(args []reflect.Value) string {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		strs[i] = diffString(arg)
+	}
+	return "(" + strings.Join(strs, ", ") + ")"
+}
+
+// generateValue generates a random value of the given type. The
+// size controls the range of numbers and the length of strings and
+// collections.
+func generateValue(g *Generator, t reflect.Type, size int) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	switch {
+	case t == durationType:
+		d := time.Duration(size) * time.Second
+		v.SetInt(int64(g.Duration(-d, d)))
+		return v, nil
+	case t == timeType:
+		base := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+		v.Set(reflect.ValueOf(g.Time(time.UTC, base, time.Duration(size)*365*24*time.Hour)))
+		return v, nil
+	}
+	limit := size * size
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(g.FlipCoin(50))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := uint(t.Bits() - 1)
+		if bits < 31 && limit > 1<<bits-1 {
+			limit = 1<<bits - 1
+		}
+		v.SetInt(int64(g.Int(-limit, limit)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		bits := uint(t.Bits())
+		if bits < 32 && limit > 1<<bits-1 {
+			limit = 1<<bits - 1
+		}
+		v.SetUint(uint64(g.Int(0, limit)))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat((g.rand.Float64()*2 - 1) * float64(limit))
+	case reflect.String:
+		runes := make([]rune, g.Int(0, size))
+		for i := range runes {
+			if g.FlipCoin(10) {
+				runes[i] = rune(g.Int(' ', '~'))
+			} else {
+				runes[i] = g.OneRuneOf("äöüßéèçñøåæ€ÄÖÜ世界")
+			}
+		}
+		v.SetString(string(runes))
+	case reflect.Slice:
+		length := g.Int(0, size)
+		v.Set(reflect.MakeSlice(t, length, length))
+		for i := 0; i < length; i++ {
+			ev, err := generateValue(g, t.Elem(), size)
+			if err != nil {
+				return v, err
+			}
+			v.Index(i).Set(ev)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			ev, err := generateValue(g, t.Elem(), size)
+			if err != nil {
+				return v, err
+			}
+			v.Index(i).Set(ev)
+		}
+	case reflect.Map:
+		length := g.Int(0, size)
+		v.Set(reflect.MakeMap(t))
+		for i := 0; i < length; i++ {
+			kv, err := generateValue(g, t.Key(), size)
+			if err != nil {
+				return v, err
+			}
+			ev, err := generateValue(g, t.Elem(), size)
+			if err != nil {
+				return v, err
+			}
+			v.SetMapIndex(kv, ev)
+		}
+	case reflect.Ptr:
+		if g.FlipCoin(90) {
+			return v, nil
+		}
+		ev, err := generateValue(g, t.Elem(), size)
+		if err != nil {
+			return v, err
+		}
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(ev)
+	default:
+		return v, fmt.Errorf("cannot generate argument of type %v", t)
+	}
+	return v, nil
+}
+
+// shrinkArgs tries to find smaller arguments still letting
+// the property fail.
+func shrinkArgs(pv reflect.Value, args []reflect.Value, err error) ([]reflect.Value, error) {
+	shrunk := make([]reflect.Value, len(args))
+	copy(shrunk, args)
+	calls := 0
+	for {
+		found := false
+	argsLoop:
+		for i, arg := range shrunk {
+			for _, candidate := range shrinkValue(arg) {
+				if calls >= maxShrinks {
+					return shrunk, err
+				}
+				calls++
+				tryArgs := make([]reflect.Value, len(shrunk))
+				copy(tryArgs, shrunk)
+				tryArgs[i] = candidate
+				if tryErr := callProperty(pv, tryArgs); tryErr != nil {
+					shrunk = tryArgs
+					err = tryErr
+					found = true
+					break argsLoop
+				}
+			}
+		}
+		if !found {
+			return shrunk, err
+		}
+	}
+}
+
+// shrinkValue returns smaller candidates of the value, the
+// smallest ones first.
+func shrinkValue(v reflect.Value) []reflect.Value {
+	candidates := []reflect.Value{}
+	add := func(set func(cv reflect.Value)) {
+		cv := reflect.New(v.Type()).Elem()
+		set(cv)
+		candidates = append(candidates, cv)
+	}
+	if v.Type() == timeType {
+		return candidates
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			add(func(cv reflect.Value) { cv.SetBool(false) })
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		if i < 0 && i != math.MinInt64 {
+			add(func(cv reflect.Value) { cv.SetInt(-i) })
+		}
+		for d := i; d != 0; d /= 2 {
+			n := i - d
+			add(func(cv reflect.Value) { cv.SetInt(n) })
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		for d := u; d != 0; d /= 2 {
+			n := u - d
+			add(func(cv reflect.Value) { cv.SetUint(n) })
+		}
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f != 0 {
+			add(func(cv reflect.Value) { cv.SetFloat(0) })
+		}
+		if t := math.Trunc(f); t != f {
+			add(func(cv reflect.Value) { cv.SetFloat(t) })
+		}
+		if h := math.Trunc(f / 2); h != 0 && h != f {
+			add(func(cv reflect.Value) { cv.SetFloat(h) })
+		}
+	case reflect.String:
+		rs := []rune(v.String())
+		for _, crs := range shrinkRunes(rs) {
+			s := string(crs)
+			add(func(cv reflect.Value) { cv.SetString(s) })
+		}
+	case reflect.Slice:
+		for _, part := range shrinkParts(v.Len()) {
+			from, to, skip := part[0], part[1], part[2]
+			add(func(cv reflect.Value) {
+				cv.Set(reflect.MakeSlice(v.Type(), 0, to-from))
+				for i := from; i < to; i++ {
+					if i != skip {
+						cv.Set(reflect.Append(cv, v.Index(i)))
+					}
+				}
+			})
+		}
+		for i := 0; i < v.Len(); i++ {
+			for _, ev := range shrinkValue(v.Index(i)) {
+				idx, ev := i, ev
+				add(func(cv reflect.Value) {
+					cv.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+					reflect.Copy(cv, v)
+					cv.Index(idx).Set(ev)
+				})
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			for _, ev := range shrinkValue(v.Index(i)) {
+				idx, ev := i, ev
+				add(func(cv reflect.Value) {
+					reflect.Copy(cv, v)
+					cv.Index(idx).Set(ev)
+				})
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		if len(keys) > 0 {
+			add(func(cv reflect.Value) { cv.Set(reflect.MakeMap(v.Type())) })
+		}
+		for _, skip := range keys {
+			skip := skip
+			add(func(cv reflect.Value) {
+				cv.Set(reflect.MakeMap(v.Type()))
+				for _, key := range keys {
+					if key.Interface() != skip.Interface() {
+						cv.SetMapIndex(key, v.MapIndex(key))
+					}
+				}
+			})
+		}
+		for _, key := range keys {
+			for _, kv := range shrinkValue(key) {
+				if v.MapIndex(kv).IsValid() {
+					continue
+				}
+				key, kv := key, kv
+				add(func(cv reflect.Value) {
+					cv.Set(reflect.MakeMap(v.Type()))
+					for _, k := range keys {
+						cv.SetMapIndex(k, v.MapIndex(k))
+					}
+					cv.SetMapIndex(key, reflect.Value{})
+					cv.SetMapIndex(kv, v.MapIndex(key))
+				})
+			}
+			for _, ev := range shrinkValue(v.MapIndex(key)) {
+				key, ev := key, ev
+				add(func(cv reflect.Value) {
+					cv.Set(reflect.MakeMap(v.Type()))
+					for _, k := range keys {
+						cv.SetMapIndex(k, v.MapIndex(k))
+					}
+					cv.SetMapIndex(key, ev)
+				})
+			}
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			add(func(cv reflect.Value) {})
+			for _, ev := range shrinkValue(v.Elem()) {
+				ev := ev
+				add(func(cv reflect.Value) {
+					cv.Set(reflect.New(v.Type().Elem()))
+					cv.Elem().Set(ev)
+				})
+			}
+		}
+	}
+	return candidates
+}
+
+// shrinkRunes returns shorter or simpler candidates of the runes.
+func shrinkRunes(rs []rune) [][]rune {
+	candidates := [][]rune{}
+	for _, part := range shrinkParts(len(rs)) {
+		from, to, skip := part[0], part[1], part[2]
+		crs := []rune{}
+		for i := from; i < to; i++ {
+			if i != skip {
+				crs = append(crs, rs[i])
+			}
+		}
+		candidates = append(candidates, crs)
+	}
+	for i, r := range rs {
+		if r != 'a' {
+			crs := make([]rune, len(rs))
+			copy(crs, rs)
+			crs[i] = 'a'
+			candidates = append(candidates, crs)
+		}
+	}
+	return candidates
+}
+
+// shrinkParts returns the parts of a sequence with the given length
+// as triples of from, to, and an index to skip. Those are the empty
+// sequence, both halves, and the sequence without one element.
+func shrinkParts(length int) [][3]int {
+	if length == 0 {
+		return nil
+	}
+	parts := [][3]int{{0, 0, -1}}
+	if length > 1 {
+		half := length / 2
+		parts = append(parts, [3]int{0, half, -1}, [3]int{half, length, -1})
+	}
+	for skip := 0; skip < length; skip++ {
+		parts = append(parts, [3]int{0, length, skip})
+	}
+	return parts
+}
+
+// EOF
@@ -0,0 +1,126 @@
+// Tideland Go Library - Audit - Unit Tests
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestPropertySuccess tests properties which hold.
+func TestPropertySuccess(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	pc := audit.NewPropertyChecker(assert, 100)
+
+	pc.Check(func(a, b int) bool {
+		return a+b == b+a
+	}, "addition is commutative")
+	pc.Check(func(s string) bool {
+		return strings.ToUpper(strings.ToUpper(s)) == strings.ToUpper(s)
+	}, "upper case is idempotent")
+	pc.Check(func(is []int, m map[string]bool, d time.Duration, tm time.Time) error {
+		if len(is) > 100 || len(m) > 100 {
+			return errors.New("too large")
+		}
+		if tm.Add(d).Sub(tm) != d {
+			return errors.New("illegal duration")
+		}
+		return nil
+	}, "collections, durations, and times")
+	pc.Check(func(b bool, u uint8, f float64, p *int16, a [3]string) bool {
+		return true
+	}, "different argument types")
+}
+
+// TestPropertyShrinking tests the shrinking of failing arguments.
+func TestPropertyShrinking(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	tests := []struct {
+		property interface{}
+		shrunk   string
+	}{
+		{func(i int) bool { return i < 100 }, "shrunk:    (100)"},
+		{func(i int) bool { return i > -50 }, "shrunk:    (-50)"},
+		{func(s string) bool { return len([]rune(s)) < 3 }, `shrunk:    ("aaa")`},
+		{func(is []int) bool { return len(is) < 2 }, "shrunk:    ([0 0])"},
+		{func(is []int) error {
+			for _, i := range is {
+				if i >= 10 {
+					return errors.New("too large")
+				}
+			}
+			return nil
+		}, "shrunk:    ([10])"},
+		{func(m map[int]string) bool { return len(m) < 1 }, "shrunk:    (map[0:])"},
+		{func(a, b int) bool {
+			if a > 5 && b > 5 {
+				panic("both too large")
+			}
+			return true
+		}, "shrunk:    (6, 6)"},
+	}
+	for i, test := range tests {
+		validation, failures := audit.NewValidationAssertion()
+		pc := audit.NewPropertyChecker(validation, 100)
+		pc.SetSeed(int64(i))
+		assert.False(pc.Check(test.property))
+		assert.Length(failures.Details(), 1)
+		assert.Substring("with seed "+string('0'+rune(i)), failures.Error().Error())
+		assert.Substring(test.shrunk, failures.Error().Error())
+	}
+}
+
+// TestPropertySeed tests the setting of the seed via
+// the environment.
+func TestPropertySeed(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	ev := audit.NewEnvVars(assert)
+	defer ev.Restore()
+
+	ev.Set(audit.PropertySeedEnv, "4711")
+	pc := audit.NewPropertyChecker(assert, 10)
+	assert.Equal(pc.Seed(), int64(4711))
+
+	collect := func() []string {
+		words := []string{}
+		pc.Check(func(s string) bool {
+			words = append(words, s)
+			return true
+		})
+		return words
+	}
+	assert.Equal(collect(), collect())
+}
+
+// TestPropertyIllegal tests illegal properties.
+func TestPropertyIllegal(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	validation, failures := audit.NewValidationAssertion()
+	pc := audit.NewPropertyChecker(validation, 10)
+
+	assert.False(pc.Check(nil))
+	assert.False(pc.Check(func(i int) int { return i }))
+	assert.False(pc.Check(func(is ...int) bool { return true }))
+	assert.False(pc.Check(func(c chan int) bool { return true }))
+	assert.Length(failures.Details(), 4)
+	assert.Substring("cannot generate argument of type chan int", failures.Error().Error())
+}
+
+// EOF
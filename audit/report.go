@@ -0,0 +1,157 @@
+// Tideland Go Library - Audit
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+//--------------------
+// JSON REPORT
+//--------------------
+
+// jsonDetail is the JSON representation of a failure detail.
+type jsonDetail struct {
+	Test      string    `json:"test"`
+	File      string    `json:"file"`
+	Line      int       `json:"line"`
+	Function  string    `json:"function"`
+	Obtained  string    `json:"obtained"`
+	Expected  string    `json:"expected"`
+	Error     string    `json:"error"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WriteJSON writes the details of the failures as JSON array
+// to the writer. Obtained and expected values are rendered as
+// strings as they may not be representable in JSON.
+func WriteJSON(w io.Writer, failures Failures) error {
+	jds := []jsonDetail{}
+	for _, detail := range failures.Details() {
+		file, line, function := detail.Location()
+		jds = append(jds, jsonDetail{
+			Test:      detail.Test().String(),
+			File:      file,
+			Line:      line,
+			Function:  function,
+			Obtained:  reportString(detail.Obtained()),
+			Expected:  reportString(detail.Expected()),
+			Error:     detail.Error().Error(),
+			Message:   detail.Message(),
+			Timestamp: detail.Timestamp(),
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jds)
+}
+
+//--------------------
+// JUNIT REPORT
+//--------------------
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite contains the test cases of one suite.
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is one failed assertion.
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	Classname string       `xml:"classname,attr"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+// junitFailure describes the failure of a test case.
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+// WriteJUnit writes the details of the failures as JUnit XML
+// report to the writer. All details are failed test cases of
+// one test suite with the given name. The case names are the
+// function names, the class names contain file and line.
+func WriteJUnit(w io.Writer, name string, failures Failures) error {
+	details := failures.Details()
+	suite := junitTestSuite{
+		Name:      name,
+		Tests:     len(details),
+		Failures:  len(details),
+		Timestamp: time.Now().Format("2006-01-02T15:04:05"),
+		Cases:     []junitTestCase{},
+	}
+	for _, detail := range details {
+		file, line, function := detail.Location()
+		content := fmt.Sprintf("Obtained: %s\nExpected: %s\nTimestamp: %s",
+			reportString(detail.Obtained()),
+			reportString(detail.Expected()),
+			detail.Timestamp().Format(time.RFC3339Nano))
+		if detail.Message() != "" {
+			content = detail.Message() + "\n" + content
+		}
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      function,
+			Classname: fmt.Sprintf("%s:%d", file, line),
+			Failure: junitFailure{
+				Message: detail.Error().Error(),
+				Type:    detail.Test().String(),
+				Content: content,
+			},
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+//--------------------
+// HELPERS
+//--------------------
+
+// reportString returns the string representation of an
+// obtained or expected value inside a report.
+func reportString(value interface{}) string {
+	switch v := value.(type) {
+	case *goldenDiff:
+		return v.path
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// EOF
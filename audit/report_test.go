@@ -0,0 +1,105 @@
+// Tideland Go Library - Audit - Unit Tests
+//
+// Copyright (C) 2012-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package audit_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/tideland/golib/audit"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestWriteJSON tests writing failures as JSON.
+func TestWriteJSON(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	va, failures := audit.NewValidationAssertion()
+
+	va.Equal(1, 2, "first")
+	va.Length("foo", 4, "second")
+
+	buffer := &bytes.Buffer{}
+	err := audit.WriteJSON(buffer, failures)
+	assert.Nil(err)
+
+	var details []map[string]interface{}
+	err = json.Unmarshal(buffer.Bytes(), &details)
+	assert.Nil(err)
+	assert.Length(details, 2)
+	assert.Equal(details[0]["test"], "equal")
+	assert.Equal(details[0]["file"], "report_test.go")
+	assert.Equal(details[0]["function"], "TestWriteJSON")
+	assert.Equal(details[0]["obtained"], "1")
+	assert.Equal(details[0]["expected"], "2")
+	assert.Equal(details[0]["message"], "first")
+	assert.Equal(details[1]["test"], "length")
+	assert.Equal(details[1]["obtained"], "3")
+	assert.Equal(details[1]["expected"], "4")
+	assert.NotNil(details[1]["timestamp"])
+
+	// No failures result in an empty array.
+	_, failures = audit.NewValidationAssertion()
+	buffer.Reset()
+	err = audit.WriteJSON(buffer, failures)
+	assert.Nil(err)
+	assert.Equal(buffer.String(), "[]\n")
+}
+
+// TestWriteJUnit tests writing failures as JUnit XML.
+func TestWriteJUnit(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	va, failures := audit.NewValidationAssertion()
+
+	va.True(false, "first")
+	va.Equal("a", "b", "second")
+
+	buffer := &bytes.Buffer{}
+	err := audit.WriteJUnit(buffer, "validation", failures)
+	assert.Nil(err)
+	assert.Substring(xml.Header, buffer.String())
+
+	var report struct {
+		Suites []struct {
+			Name     string `xml:"name,attr"`
+			Tests    int    `xml:"tests,attr"`
+			Failures int    `xml:"failures,attr"`
+			Cases    []struct {
+				Name      string `xml:"name,attr"`
+				Classname string `xml:"classname,attr"`
+				Failure   struct {
+					Message string `xml:"message,attr"`
+					Type    string `xml:"type,attr"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	err = xml.Unmarshal(buffer.Bytes(), &report)
+	assert.Nil(err)
+	assert.Length(report.Suites, 1)
+	suite := report.Suites[0]
+	assert.Equal(suite.Name, "validation")
+	assert.Equal(suite.Tests, 2)
+	assert.Equal(suite.Failures, 2)
+	assert.Length(suite.Cases, 2)
+	assert.Equal(suite.Cases[0].Name, "TestWriteJUnit")
+	assert.Match(suite.Cases[0].Classname, `^report_test\.go:\d+$`)
+	assert.Equal(suite.Cases[0].Failure.Type, "true")
+	assert.Equal(suite.Cases[1].Failure.Type, "equal")
+	assert.Substring("second", suite.Cases[1].Failure.Message)
+}
+
+// EOF
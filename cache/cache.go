@@ -16,7 +16,9 @@ import (
 
 	"github.com/tideland/golib/errors"
 	"github.com/tideland/golib/identifier"
+	"github.com/tideland/golib/logger"
 	"github.com/tideland/golib/loop"
+	"github.com/tideland/golib/timex"
 )
 
 //--------------------
@@ -29,6 +31,7 @@ type bucketStatus int
 const (
 	statusLoading bucketStatus = iota + 1
 	statusLoaded
+	statusFailed
 )
 
 //--------------------
@@ -55,6 +58,10 @@ type Cacheable interface {
 // loading/reloading of cacheable instances.
 type CacheableLoader func(id string) (Cacheable, error)
 
+// ErrorMatcher checks if an error returned by a CacheableLoader
+// shall be cached, see NegativeMatcher.
+type ErrorMatcher func(err error) bool
+
 //--------------------
 // OPTIONS
 //--------------------
@@ -114,6 +121,199 @@ func TTL(d time.Duration) Option {
 	}
 }
 
+// MaxAge returns the option to set the age after which loaded
+// Cacheables expire and are reloaded on the next lookup, regardless
+// if they are outdated or not. Default is 0 for no expiry.
+func MaxAge(d time.Duration) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.maxAge = d
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// RefreshAhead returns the option to set the duration before the
+// expiry of Cacheables in which lookups start a reload in the
+// background. Until it's done the current Cacheable is returned.
+// It only works together with MaxAge.
+func RefreshAhead(d time.Duration) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.refreshAhead = d
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// MaxStale returns the option to set the duration after the expiry
+// of Cacheables in which lookups still return the stale Cacheable
+// while it's reloaded in the background. Later lookups wait for the
+// reload. It only works together with MaxAge.
+func MaxStale(d time.Duration) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.maxStale = d
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// NegativeTTL returns the option to set the duration errors of
+// the loader are cached. During this time lookups of the same ID
+// return the error without calling the loader again. Default is 0
+// for no caching of errors.
+func NegativeTTL(d time.Duration) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.negativeTTL = d
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// NegativeMatcher returns the option to set a matcher deciding
+// which errors of the loader are cached, e.g. IsFileNotExist for
+// the file loader. Default is nil for caching all errors.
+func NegativeMatcher(m ErrorMatcher) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.negativeMatcher = m
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// MaxEntries returns the option to set the maximum number of
+// Cacheables. If it's exceeded Cacheables are evicted according to
+// the eviction policy. Default is 0 for no limit.
+func MaxEntries(n int) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.maxEntries = n
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// MaxWeight returns the option to set the maximum total weight of
+// the Cacheables, see Weigher. If it's exceeded Cacheables are evicted
+// according to the eviction policy. Default is 0 for no limit.
+func MaxWeight(w int64) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.maxWeight = w
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// Eviction returns the option to set the policy deciding which
+// Cacheables are evicted if the maximum number of entries or the
+// maximum weight is exceeded. Default is NewLRU().
+func Eviction(policy EvictionPolicy) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.policy = policy
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// Monitoring returns the option to switch on the monitoring
+// of the cache. Counters of the statistics are then additionally
+// set as stay-set variables, loads are measured. Their IDs are
+// "cache:<id>:<name>" with the unchanged ID of the cache and the
+// names "hits", "misses", "loads", "load-errors", "negative-hits",
+// "outdated-reloads", "refreshes", "evictions", and "load" for the
+// measuring point. Default is off.
+func Monitoring(monitoring bool) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.monitoring = monitoring
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// Snapshot returns the option to write the Cacheables into a snapshot
+// file when the cache is stopped. The snapshot is read when the cache
+// is created again, so it starts warm. Only Cacheables not outdated
+// are restored. The marshaller and unmarshaller serialize the
+// Cacheables. Default is no snapshot.
+func Snapshot(path string, marshal Marshaller, unmarshal Unmarshaller) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.snapshot = &snapshot{
+				path:      path,
+				marshal:   marshal,
+				unmarshal: unmarshal,
+			}
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// SnapshotInterval returns the option to additionally write the
+// snapshot in intervals. It only works together with Snapshot.
+// Default is 0 for writing it only when the cache is stopped.
+func SnapshotInterval(d time.Duration) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.snapshotInterval = d
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
+// Clock returns the option to set the clock used for the cleanup
+// interval and the time to live. Timeouts of the operations
+// still use the real time. Default is the real clock.
+func Clock(clock timex.Clock) Option {
+	return func(c Cache) error {
+		switch oc := c.(type) {
+		case *cache:
+			oc.clock = clock
+			return nil
+		default:
+			return errors.New(ErrIllegalCache, errorMessages)
+		}
+	}
+}
+
 //--------------------
 // INFO
 //--------------------
@@ -145,6 +345,9 @@ type Cache interface {
 	// Len returns the number of entries in the Cache.
 	Len() int
 
+	// Stats returns the usage statistics of the Cache.
+	Stats() Stats
+
 	// Stop tells the Cache to stop working.
 	Stop() error
 }
@@ -155,29 +358,51 @@ type responder chan func() (Cacheable, error)
 
 // bucket contains a Cacheable and the data needed to manage it.
 type bucket struct {
-	cacheable Cacheable
-	status    bucketStatus
-	loaded    time.Time
-	lastUsed  time.Time
-	waiters   []responder
+	cacheable  Cacheable
+	status     bucketStatus
+	loaded     time.Time
+	lastUsed   time.Time
+	weight     int64
+	refreshing bool
+	err        error
+	waiters    []responder
 }
 
 // cache implements the Cache interface.
 type cache struct {
-	id       string
-	load     CacheableLoader
-	interval time.Duration
-	ttl      time.Duration
-	buckets  map[string]*bucket
-	taskc    chan task
-	lenc     chan chan int
-	backend  loop.Loop
+	id               string
+	load             CacheableLoader
+	clock            timex.Clock
+	interval         time.Duration
+	ttl              time.Duration
+	maxAge           time.Duration
+	refreshAhead     time.Duration
+	maxStale         time.Duration
+	negativeTTL      time.Duration
+	negativeMatcher  ErrorMatcher
+	maxEntries       int
+	maxWeight        int64
+	policy           EvictionPolicy
+	entries          int
+	weight           int64
+	monitoring       bool
+	stats            Stats
+	loadCount        int64
+	loadTotal        time.Duration
+	snapshot         *snapshot
+	snapshotInterval time.Duration
+	checker          timex.Ticker
+	buckets          map[string]*bucket
+	taskc            chan task
+	lenc             chan chan int
+	backend          loop.Loop
 }
 
 // New creates a new cache.
 func New(options ...Option) (Cache, error) {
 	c := &cache{
 		id:       identifier.NewUUID().String(),
+		clock:    timex.RealClock(),
 		interval: time.Minute,
 		ttl:      10 * time.Minute,
 		buckets:  make(map[string]*bucket),
@@ -192,6 +417,13 @@ func New(options ...Option) (Cache, error) {
 	if c.load == nil {
 		return nil, errors.New(ErrNoLoader, errorMessages)
 	}
+	if c.policy == nil {
+		c.policy = NewLRU()
+	}
+	if c.snapshot != nil {
+		c.readSnapshot()
+	}
+	c.checker = c.clock.NewTicker(c.interval)
 	c.backend = loop.Go(c.backendLoop, "cache", c.id)
 	return c, nil
 }
@@ -251,6 +483,15 @@ func (c *cache) Len() int {
 	return l
 }
 
+// Stats implements the Cache interface.
+func (c *cache) Stats() Stats {
+	// Send stats task.
+	statsc := make(chan Stats, 1)
+	c.taskc <- statsTask(statsc)
+	// Receive response.
+	return <-statsc
+}
+
 // Stop implements the Cache interface.
 func (c *cache) Stop() error {
 	return c.backend.Stop()
@@ -258,21 +499,34 @@ func (c *cache) Stop() error {
 
 // backendLoop runs the cache.
 func (c *cache) backendLoop(l loop.Loop) error {
-	// Prepare ticker for lifetime check.
-	checker := time.NewTicker(c.interval)
-	defer checker.Stop()
+	// Stop ticker for lifetime check at the end.
+	defer c.checker.Stop()
+	// Write snapshots in intervals if wanted.
+	var snapshotc <-chan time.Time
+	if c.snapshot != nil && c.snapshotInterval > 0 {
+		ticker := c.clock.NewTicker(c.snapshotInterval)
+		defer ticker.Stop()
+		snapshotc = ticker.C()
+	}
 	// Run loop.
 	for {
 		select {
 		case <-l.ShallStop():
+			if c.snapshot != nil {
+				return c.writeSnapshot()
+			}
 			return nil
+		case <-snapshotc:
+			if err := c.writeSnapshot(); err != nil {
+				logger.Errorf("cache %q cannot write snapshot: %v", c.id, err)
+			}
 		case do := <-c.taskc:
 			if err := do(c); err != nil {
 				return err
 			}
 		case lenc := <-c.lenc:
 			lenc <- len(c.buckets)
-		case <-checker.C:
+		case <-c.checker.C():
 			if err := c.cleanup(); err != nil {
 				return err
 			}
@@ -284,12 +538,18 @@ func (c *cache) backendLoop(l loop.Loop) error {
 // and removes them.
 func (c *cache) cleanup() error {
 	unused := []string{}
-	now := time.Now()
+	now := c.clock.Now()
 	// First find old ones.
 	for id, bucket := range c.buckets {
 		if bucket.status == statusLoading {
 			continue
 		}
+		if bucket.status == statusFailed {
+			if bucket.loaded.Add(c.negativeTTL).Before(now) {
+				unused = append(unused, id)
+			}
+			continue
+		}
 		if bucket.lastUsed.Add(c.ttl).Before(now) {
 			unused = append(unused, id)
 		}
@@ -298,7 +558,10 @@ func (c *cache) cleanup() error {
 	var errs []error
 	for _, id := range unused {
 		cacheable := c.buckets[id].cacheable
-		delete(c.buckets, id)
+		c.remove(id)
+		if cacheable == nil {
+			continue
+		}
 		if err := cacheable.Discard(); err != nil {
 			errs = append(errs, err)
 		}
@@ -309,4 +572,55 @@ func (c *cache) cleanup() error {
 	return nil
 }
 
+// remove deletes a bucket and tells the eviction
+// policy about it.
+func (c *cache) remove(id string) {
+	b, ok := c.buckets[id]
+	if !ok {
+		return
+	}
+	if b.cacheable != nil {
+		c.entries--
+		c.weight -= b.weight
+		c.policy.Removed(id)
+	}
+	delete(c.buckets, id)
+}
+
+// exceeds checks if the cache would exceed its limits with
+// the additional entries and weight.
+func (c *cache) exceeds(entries int, weight int64) bool {
+	return c.maxEntries > 0 && c.entries+entries > c.maxEntries ||
+		c.maxWeight > 0 && c.weight+weight > c.maxWeight
+}
+
+// evict discards Cacheables chosen by the eviction policy until
+// the additional entries and weight fit into the cache. Waiters
+// of Cacheables currently reloading are notified. Errors while
+// discarding are only logged, they must not stop the cache.
+func (c *cache) evict(entries int, weight int64) {
+	for c.exceeds(entries, weight) {
+		id, ok := c.policy.Victim()
+		if !ok {
+			break
+		}
+		b, ok := c.buckets[id]
+		if !ok || b.cacheable == nil {
+			continue
+		}
+		for _, waiter := range b.waiters {
+			waiter <- func() (Cacheable, error) {
+				return nil, errors.New(ErrDiscardedWhileLoading, errorMessages, id)
+			}
+		}
+		c.entries--
+		c.weight -= b.weight
+		c.count(&c.stats.Evictions, monitorEvictions)
+		delete(c.buckets, id)
+		if err := b.cacheable.Discard(); err != nil {
+			logger.Warningf("cache %q cannot discard evicted %q: %v", c.id, id, err)
+		}
+	}
+}
+
 // EOF
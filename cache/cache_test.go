@@ -13,6 +13,7 @@ package cache_test
 
 import (
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -21,6 +22,8 @@ import (
 	"github.com/tideland/golib/audit"
 	"github.com/tideland/golib/cache"
 	"github.com/tideland/golib/errors"
+	"github.com/tideland/golib/monitoring"
+	"github.com/tideland/golib/timex"
 )
 
 //--------------------
@@ -38,6 +41,7 @@ const (
 	idSuccessfulDiscarding  = "/successful/discarding"
 	idConcurrent            = "/concurrent"
 	idCleanup               = "/cleanup/%d"
+	idEviction              = "/eviction/%d"
 )
 
 //--------------------
@@ -156,6 +160,12 @@ func TestOutdatingReload(t *testing.T) {
 	_, err = c.Load(idIsOutdated, time.Second)
 	assert.Nil(err)
 	assert.Equal(te.loaded[idIsOutdated], 1)
+
+	// The outdated one is no hit.
+	stats := c.Stats()
+	assert.Equal(stats.Hits, int64(3))
+	assert.Equal(stats.OutdatedReloads, int64(1))
+	assert.Equal(stats.Misses, int64(2))
 }
 
 // TestOutdatingReloadError tests an error during reload of
@@ -248,6 +258,37 @@ func TestCleanup(t *testing.T) {
 	assert.True(firstLen > secondLen)
 }
 
+// TestCleanupFakeClock tests the cleanup of unused Cacheables
+// controlled by a fake clock.
+func TestCleanupFakeClock(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	te := initEnvironment()
+	clock := timex.NewFakeClock(time.Now())
+
+	c, err := cache.New(cache.ID("cleanup-fake-clock"), cache.Loader(te.loader),
+		cache.Clock(clock), cache.Interval(time.Minute), cache.TTL(5*time.Minute))
+	assert.Nil(err)
+	defer c.Stop()
+
+	// Fill the cache.
+	for i := 0; i < 10; i++ {
+		_, err := c.Load(fmt.Sprintf(idCleanup, i), time.Second)
+		assert.Nil(err)
+	}
+	clock.Advance(4 * time.Minute)
+	assert.Equal(c.Len(), 10)
+
+	// Use half of them again.
+	for i := 0; i < 5; i++ {
+		_, err := c.Load(fmt.Sprintf(idCleanup, i), time.Second)
+		assert.Nil(err)
+	}
+	clock.Advance(2 * time.Minute)
+	assert.Retry(func() bool {
+		return c.Len() == 5
+	}, 100, 10*time.Millisecond)
+}
+
 // TestClear tests the clearing of a Cache.
 func TestClear(t *testing.T) {
 	assert := audit.NewTestingAssertion(t, true)
@@ -272,6 +313,348 @@ func TestClear(t *testing.T) {
 	assert.True(firstLen > secondLen)
 }
 
+// TestRefreshAhead tests the refreshing of Cacheables in the
+// background before and after their expiry.
+func TestRefreshAhead(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	te := initEnvironment()
+	clock := timex.NewFakeClock(time.Now())
+
+	c, err := cache.New(cache.ID("refresh-ahead"), cache.Loader(te.loader),
+		cache.Clock(clock), cache.Interval(time.Hour),
+		cache.MaxAge(time.Minute), cache.RefreshAhead(10*time.Second),
+		cache.MaxStale(30*time.Second))
+	assert.Nil(err)
+	defer c.Stop()
+	loads := func(n int64) func() bool {
+		return func() bool {
+			return c.Stats().Loads == n
+		}
+	}
+
+	first, err := c.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+
+	// Shortly before expiry the current one is returned
+	// without waiting for the reload.
+	clock.Advance(55 * time.Second)
+	cacheable, err := c.Load(idValidCacheable, 20*time.Millisecond)
+	assert.Nil(err)
+	assert.True(cacheable == first)
+	assert.Retry(loads(2), 100, 10*time.Millisecond)
+	second, err := c.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+	assert.True(second != first)
+	assert.Equal(te.discardedIDs(), []string{idValidCacheable})
+
+	// Shortly after expiry the stale one is returned.
+	clock.Advance(80 * time.Second)
+	cacheable, err = c.Load(idValidCacheable, 20*time.Millisecond)
+	assert.Nil(err)
+	assert.True(cacheable == second)
+	assert.Retry(loads(3), 100, 10*time.Millisecond)
+	third, err := c.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+	assert.True(third != second)
+
+	// Too long after expiry the reload is awaited.
+	clock.Advance(2 * time.Minute)
+	_, err = c.Load(idValidCacheable, 20*time.Millisecond)
+	assert.ErrorMatch(err, ".*timeout.*")
+	cacheable, err = c.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+	assert.True(cacheable != third)
+
+	stats := c.Stats()
+	assert.Equal(stats.Loads, int64(4))
+	assert.Equal(stats.Refreshes, int64(2))
+}
+
+// TestRefreshAheadFailed tests that waiters for a too stale
+// Cacheable are notified if the refreshing fails.
+func TestRefreshAheadFailed(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	te := initEnvironment()
+	clock := timex.NewFakeClock(time.Now())
+	releasec := make(chan struct{})
+	loads := int32(0)
+	loader := func(id string) (cache.Cacheable, error) {
+		if atomic.AddInt32(&loads, 1) == 1 {
+			return te.loader(id)
+		}
+		<-releasec
+		return nil, errors.New(errLoading, errorMessages)
+	}
+
+	c, err := cache.New(cache.ID("refresh-ahead-failed"), cache.Loader(loader),
+		cache.Clock(clock), cache.Interval(time.Hour),
+		cache.MaxAge(time.Minute), cache.RefreshAhead(10*time.Second),
+		cache.MaxStale(30*time.Second))
+	assert.Nil(err)
+	defer c.Stop()
+
+	first, err := c.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+
+	// Start refreshing in the background.
+	clock.Advance(55 * time.Second)
+	cacheable, err := c.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+	assert.True(cacheable == first)
+
+	// Now it's too stale, so wait for the refreshing.
+	clock.Advance(2 * time.Minute)
+	errc := make(chan error)
+	go func() {
+		_, err := c.Load(idValidCacheable, 5*time.Second)
+		errc <- err
+	}()
+	assert.Retry(func() bool {
+		return c.Stats().Misses == 2
+	}, 100, 10*time.Millisecond)
+	close(releasec)
+	select {
+	case err = <-errc:
+		assert.ErrorMatch(err, ".*cannot load cacheable.*error during loading.*")
+	case <-time.After(time.Second):
+		assert.Fail("waiter has not been notified")
+	}
+	assert.Length(te.discardedIDs(), 0)
+}
+
+// TestClearWhileRefreshing tests that clearing a cache discards
+// the Cacheables currently refreshed too.
+func TestClearWhileRefreshing(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	te := initEnvironment()
+	clock := timex.NewFakeClock(time.Now())
+	releasec := make(chan struct{})
+	loads := int32(0)
+	loader := func(id string) (cache.Cacheable, error) {
+		if atomic.AddInt32(&loads, 1) > 1 {
+			<-releasec
+		}
+		return te.loader(id)
+	}
+
+	c, err := cache.New(cache.ID("clear-while-refreshing"), cache.Loader(loader),
+		cache.Clock(clock), cache.Interval(time.Hour),
+		cache.MaxAge(time.Minute), cache.RefreshAhead(10*time.Second))
+	assert.Nil(err)
+	defer c.Stop()
+
+	first, err := c.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+
+	// Start refreshing in the background and clear.
+	clock.Advance(55 * time.Second)
+	cacheable, err := c.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+	assert.True(cacheable == first)
+	assert.Retry(func() bool {
+		return atomic.LoadInt32(&loads) == 2
+	}, 100, 10*time.Millisecond)
+	err = c.Clear()
+	assert.Nil(err)
+	assert.Equal(te.discardedIDs(), []string{idValidCacheable})
+	assert.Equal(c.Len(), 0)
+
+	// The refreshed one is discarded when it arrives.
+	close(releasec)
+	assert.Retry(func() bool {
+		return len(te.discardedIDs()) == 2
+	}, 100, 10*time.Millisecond)
+	assert.Equal(c.Len(), 0)
+}
+
+// TestNegativeCaching tests the caching of loader errors.
+func TestNegativeCaching(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	te := initEnvironment()
+	clock := timex.NewFakeClock(time.Now())
+
+	c, err := cache.New(cache.ID("negative-caching"), cache.Loader(te.loader),
+		cache.Clock(clock), cache.NegativeTTL(time.Minute))
+	assert.Nil(err)
+	defer c.Stop()
+
+	for i := 0; i < 3; i++ {
+		_, err := c.Load(idErrorDuringLoading, time.Second)
+		assert.ErrorMatch(err, ".*cannot load cacheable '/error/during/loading'.*error during loading.*")
+	}
+	stats := c.Stats()
+	assert.Equal(stats.LoadErrors, int64(1))
+	assert.Equal(stats.NegativeHits, int64(2))
+
+	// After the TTL the loader is called again.
+	clock.Advance(2 * time.Minute)
+	_, err = c.Load(idErrorDuringLoading, time.Second)
+	assert.ErrorMatch(err, ".*error during loading.*")
+	stats = c.Stats()
+	assert.Equal(stats.LoadErrors, int64(2))
+	assert.Equal(stats.NegativeHits, int64(2))
+
+	// Not matching errors are not cached.
+	m, err := cache.New(cache.ID("negative-matcher"), cache.Loader(te.loader),
+		cache.NegativeTTL(time.Minute), cache.NegativeMatcher(func(err error) bool {
+			return !errors.IsError(err, errLoading)
+		}))
+	assert.Nil(err)
+	defer m.Stop()
+
+	for i := 0; i < 3; i++ {
+		_, err := m.Load(idErrorDuringLoading, time.Second)
+		assert.ErrorMatch(err, ".*error during loading.*")
+	}
+	stats = m.Stats()
+	assert.Equal(stats.LoadErrors, int64(3))
+	assert.Equal(stats.NegativeHits, int64(0))
+}
+
+// TestMaxEntries tests the eviction of Cacheables if the
+// maximum number of entries is exceeded.
+func TestMaxEntries(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	te := initEnvironment()
+
+	c, err := cache.New(cache.ID("max-entries"), cache.Loader(te.loader),
+		cache.MaxEntries(3))
+	assert.Nil(err)
+	defer c.Stop()
+
+	for i := 0; i < 5; i++ {
+		_, err := c.Load(fmt.Sprintf(idEviction, i), time.Second)
+		assert.Nil(err)
+		assert.True(c.Len() <= 3)
+	}
+	assert.Equal(c.Len(), 3)
+	assert.Equal(te.discardedIDs(), []string{"/eviction/0", "/eviction/1"})
+}
+
+// TestEvictionDiscardError tests that an error while discarding
+// an evicted Cacheable doesn't stop the cache.
+func TestEvictionDiscardError(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	te := initEnvironment()
+
+	c, err := cache.New(cache.ID("eviction-discard-error"), cache.Loader(te.loader),
+		cache.MaxEntries(1))
+	assert.Nil(err)
+	defer c.Stop()
+
+	_, err = c.Load(idErrorDuringDiscarding, time.Second)
+	assert.Nil(err)
+	for i := 0; i < 3; i++ {
+		_, err = c.Load(fmt.Sprintf(idEviction, i), time.Second)
+		assert.Nil(err)
+	}
+	assert.Equal(c.Len(), 1)
+	assert.Equal(c.Stats().Evictions, int64(3))
+}
+
+// TestEvictionPolicies tests the different eviction policies.
+func TestEvictionPolicies(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	tests := []struct {
+		name     string
+		policy   cache.EvictionPolicy
+		accesses []int
+		evicted  string
+	}{
+		{"lru", cache.NewLRU(), []int{0, 2}, "/eviction/1"},
+		{"lfu", cache.NewLFU(), []int{0, 0, 1, 2, 2}, "/eviction/1"},
+		{"lfu-ties", cache.NewLFU(), []int{2, 1, 0}, "/eviction/2"},
+		{"arc", cache.NewARC(), []int{0}, "/eviction/1"},
+	}
+	for i, test := range tests {
+		assert.Logf("test #%d: %s", i, test.name)
+		te := initEnvironment()
+		c, err := cache.New(cache.ID("eviction-"+test.name), cache.Loader(te.loader),
+			cache.MaxEntries(3), cache.Eviction(test.policy))
+		assert.Nil(err)
+
+		for j := 0; j < 3; j++ {
+			_, err := c.Load(fmt.Sprintf(idEviction, j), time.Second)
+			assert.Nil(err)
+		}
+		for _, j := range test.accesses {
+			_, err := c.Load(fmt.Sprintf(idEviction, j), time.Second)
+			assert.Nil(err)
+		}
+		_, err = c.Load(fmt.Sprintf(idEviction, 3), time.Second)
+		assert.Nil(err)
+		assert.Equal(c.Len(), 3)
+		assert.Equal(te.discardedIDs(), []string{test.evicted})
+
+		err = c.Stop()
+		assert.Nil(err)
+	}
+}
+
+// TestStats tests the statistics of a Cache.
+func TestStats(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	te := initEnvironment()
+
+	c, err := cache.New(cache.ID("stats"), cache.Loader(te.loader),
+		cache.MaxEntries(2))
+	assert.Nil(err)
+	defer c.Stop()
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			_, err := c.Load(fmt.Sprintf(idEviction, i), time.Second)
+			assert.Nil(err)
+		}
+	}
+	_, err = c.Load(idErrorDuringLoading, time.Second)
+	assert.ErrorMatch(err, ".* error during loading")
+
+	stats := c.Stats()
+	assert.Equal(stats.ID, "stats")
+	assert.Equal(stats.Len, 2)
+	assert.Equal(stats.Weight, int64(2))
+	assert.Equal(stats.Hits, int64(6))
+	assert.Equal(stats.Misses, int64(4))
+	assert.Equal(stats.Loads, int64(3))
+	assert.Equal(stats.LoadErrors, int64(1))
+	assert.Equal(stats.Evictions, int64(1))
+	assert.Equal(stats.OutdatedReloads, int64(0))
+	assert.True(stats.MinLoadTime <= stats.AvgLoadTime)
+	assert.True(stats.AvgLoadTime <= stats.MaxLoadTime)
+	assert.True(stats.MaxLoadTime >= 50*time.Millisecond)
+}
+
+// TestStatsMonitoring tests the statistics passed
+// to the monitoring.
+func TestStatsMonitoring(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	te := initEnvironment()
+	monitoring.SetBackend(monitoring.NewStandardBackend())
+
+	c, err := cache.New(cache.ID("Stats/Monitoring"), cache.Loader(te.loader),
+		cache.Monitoring(true))
+	assert.Nil(err)
+	defer c.Stop()
+
+	for i := 0; i < 3; i++ {
+		_, err := c.Load(idValidCacheable, time.Second)
+		assert.Nil(err)
+	}
+	assert.Retry(func() bool {
+		hits, err := monitoring.ReadVariable("cache:Stats/Monitoring:hits")
+		if err != nil || hits.ActValue() != 2 {
+			return false
+		}
+		misses, err := monitoring.ReadVariable("cache:Stats/Monitoring:misses")
+		if err != nil || misses.ActValue() != 1 {
+			return false
+		}
+		mp, err := monitoring.ReadMeasuringPoint("cache:Stats/Monitoring:load")
+		return err == nil && mp.Count() == 1
+	}, 100, 10*time.Millisecond)
+}
+
 //--------------------
 // HELPERS
 //--------------------
@@ -291,9 +674,10 @@ var errorMessages = errors.Messages{
 }
 
 type testEnvironment struct {
-	mutex    sync.Mutex
-	loaded   map[string]int
-	reloaded map[string]bool
+	mutex     sync.Mutex
+	loaded    map[string]int
+	reloaded  map[string]bool
+	discarded []string
 }
 
 // initEnvironment creates a new test environment.
@@ -304,6 +688,14 @@ func initEnvironment() *testEnvironment {
 	}
 }
 
+// discardedIDs returns the IDs of the discarded
+// testCacheables in order.
+func (te *testEnvironment) discardedIDs() []string {
+	te.mutex.Lock()
+	defer te.mutex.Unlock()
+	return append([]string{}, te.discarded...)
+}
+
 // loader loads the testCacheable.
 func (te *testEnvironment) loader(id string) (cache.Cacheable, error) {
 	switch id {
@@ -372,6 +764,9 @@ func (tc *testCacheable) Discard() error {
 		return errors.New(errDoubleDiscarding, errorMessages, tc.id)
 	}
 	tc.discarded = true
+	tc.te.mutex.Lock()
+	tc.te.discarded = append(tc.te.discarded, tc.id)
+	tc.te.mutex.Unlock()
 	return nil
 }
 
@@ -32,6 +32,11 @@ const (
 	ErrFileLoading
 	ErrFileSize
 	ErrFileChecking
+	ErrTier
+	ErrInvalidation
+	ErrSnapshot
+	ErrFileWatching
+	ErrIllegalCacheable
 )
 
 var errorMessages = errors.Messages{
@@ -46,6 +51,11 @@ var errorMessages = errors.Messages{
 	ErrFileLoading:           "cannot load file '%s'",
 	ErrFileSize:              "file '%s' is too large",
 	ErrFileChecking:          "cannot check file '%s'",
+	ErrTier:                  "cannot access '%s' in tier",
+	ErrInvalidation:          "cannot use invalidation channel '%s'",
+	ErrSnapshot:              "cannot write snapshot '%s'",
+	ErrFileWatching:          "cannot watch files in '%s'",
+	ErrIllegalCacheable:      "cacheable '%s' has not been loaded by the typed cache",
 }
 
 // EOF
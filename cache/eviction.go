@@ -0,0 +1,353 @@
+// Tideland Go Library - Cache - Eviction
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"container/heap"
+	"container/list"
+)
+
+//--------------------
+// WEIGHER
+//--------------------
+
+// Weigher can be implemented by Cacheables to tell their weight,
+// e.g. their size in bytes, for caches with a maximum weight. Other
+// Cacheables weigh 1.
+type Weigher interface {
+	// Weight returns the weight of the Cacheable.
+	Weight() int64
+}
+
+// weightOf returns the weight of the Cacheable.
+func weightOf(cacheable Cacheable) int64 {
+	if w, ok := cacheable.(Weigher); ok {
+		return w.Weight()
+	}
+	return 1
+}
+
+//--------------------
+// EVICTION POLICY
+//--------------------
+
+// EvictionPolicy decides which Cacheable is evicted if a cache
+// exceeds its maximum number of entries or weight. It is only used
+// by the backend of one cache, so it needs no synchronization and
+// must not be shared between caches.
+type EvictionPolicy interface {
+	// Added tells the policy that a Cacheable has been loaded.
+	Added(id string)
+
+	// Accessed tells the policy that a Cacheable has been used.
+	Accessed(id string)
+
+	// Removed tells the policy that a Cacheable has been removed
+	// for other reasons than eviction.
+	Removed(id string)
+
+	// Victim returns the ID of the next Cacheable to evict and
+	// forgets it. It returns false if there's none.
+	Victim() (string, bool)
+}
+
+//--------------------
+// LRU
+//--------------------
+
+// lru implements the least recently used eviction.
+type lru struct {
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// NewLRU returns the policy evicting the least recently
+// used Cacheables. It's the default policy.
+func NewLRU() EvictionPolicy {
+	return &lru{
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Added implements the EvictionPolicy interface.
+func (p *lru) Added(id string) {
+	if e, ok := p.entries[id]; ok {
+		p.order.MoveToFront(e)
+		return
+	}
+	p.entries[id] = p.order.PushFront(id)
+}
+
+// Accessed implements the EvictionPolicy interface.
+func (p *lru) Accessed(id string) {
+	if e, ok := p.entries[id]; ok {
+		p.order.MoveToFront(e)
+	}
+}
+
+// Removed implements the EvictionPolicy interface.
+func (p *lru) Removed(id string) {
+	if e, ok := p.entries[id]; ok {
+		p.order.Remove(e)
+		delete(p.entries, id)
+	}
+}
+
+// Victim implements the EvictionPolicy interface.
+func (p *lru) Victim() (string, bool) {
+	e := p.order.Back()
+	if e == nil {
+		return "", false
+	}
+	id := p.order.Remove(e).(string)
+	delete(p.entries, id)
+	return id, true
+}
+
+//--------------------
+// LFU
+//--------------------
+
+// lfuEntry contains the usage of a Cacheable.
+type lfuEntry struct {
+	id    string
+	count int
+	tick  int
+	index int
+}
+
+// lfuHeap orders the entries by usage count and
+// then by last usage.
+type lfuHeap []*lfuEntry
+
+// Len implements the heap.Interface.
+func (h lfuHeap) Len() int { return len(h) }
+
+// Less implements the heap.Interface.
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].count == h[j].count {
+		return h[i].tick < h[j].tick
+	}
+	return h[i].count < h[j].count
+}
+
+// Swap implements the heap.Interface.
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+// Push implements the heap.Interface.
+func (h *lfuHeap) Push(x interface{}) {
+	e := x.(*lfuEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+// Pop implements the heap.Interface.
+func (h *lfuHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	*h = old[:n-1]
+	return e
+}
+
+// lfu implements the least frequently used eviction.
+type lfu struct {
+	tick    int
+	heap    lfuHeap
+	entries map[string]*lfuEntry
+}
+
+// NewLFU returns the policy evicting the least frequently
+// used Cacheables. Ties are broken by evicting the least
+// recently used one.
+func NewLFU() EvictionPolicy {
+	return &lfu{
+		entries: make(map[string]*lfuEntry),
+	}
+}
+
+// Added implements the EvictionPolicy interface.
+func (p *lfu) Added(id string) {
+	if _, ok := p.entries[id]; ok {
+		p.Accessed(id)
+		return
+	}
+	p.tick++
+	e := &lfuEntry{id: id, count: 1, tick: p.tick}
+	heap.Push(&p.heap, e)
+	p.entries[id] = e
+}
+
+// Accessed implements the EvictionPolicy interface.
+func (p *lfu) Accessed(id string) {
+	if e, ok := p.entries[id]; ok {
+		p.tick++
+		e.count++
+		e.tick = p.tick
+		heap.Fix(&p.heap, e.index)
+	}
+}
+
+// Removed implements the EvictionPolicy interface.
+func (p *lfu) Removed(id string) {
+	if e, ok := p.entries[id]; ok {
+		heap.Remove(&p.heap, e.index)
+		delete(p.entries, id)
+	}
+}
+
+// Victim implements the EvictionPolicy interface.
+func (p *lfu) Victim() (string, bool) {
+	if len(p.heap) == 0 {
+		return "", false
+	}
+	e := heap.Pop(&p.heap).(*lfuEntry)
+	delete(p.entries, e.id)
+	return e.id, true
+}
+
+//--------------------
+// ARC
+//--------------------
+
+// arc implements the adaptive replacement cache eviction.
+type arc struct {
+	size    int
+	target  int
+	t1, t2  *list.List
+	b1, b2  *list.List
+	entries map[string]*arcEntry
+}
+
+// arcEntry locates a Cacheable or its ghost in the lists.
+type arcEntry struct {
+	list    *list.List
+	element *list.Element
+}
+
+// NewARC returns the policy balancing between recently and
+// frequently used Cacheables. It remembers evicted IDs to adapt
+// the balance when they are loaded again. As the number of
+// entries of a cache limited by weight varies, the number of
+// remembered IDs is bound to the largest number of entries so far.
+func NewARC() EvictionPolicy {
+	return &arc{
+		t1:      list.New(),
+		t2:      list.New(),
+		b1:      list.New(),
+		b2:      list.New(),
+		entries: make(map[string]*arcEntry),
+	}
+}
+
+// Added implements the EvictionPolicy interface.
+func (p *arc) Added(id string) {
+	e, ok := p.entries[id]
+	switch {
+	case !ok:
+		p.push(id, p.t1)
+	case e.list == p.b1:
+		// Recently evicted, so favor recent ones.
+		p.target = minInt(p.target+maxInt(p.b2.Len()/p.b1.Len(), 1), p.capacity())
+		p.move(id, e, p.t2)
+	case e.list == p.b2:
+		// Frequently used evicted, so favor frequent ones.
+		p.target = maxInt(p.target-maxInt(p.b1.Len()/p.b2.Len(), 1), 0)
+		p.move(id, e, p.t2)
+	default:
+		p.move(id, e, p.t2)
+	}
+	p.size = maxInt(p.size, p.t1.Len()+p.t2.Len())
+	p.trim()
+}
+
+// Accessed implements the EvictionPolicy interface.
+func (p *arc) Accessed(id string) {
+	if e, ok := p.entries[id]; ok && (e.list == p.t1 || e.list == p.t2) {
+		p.move(id, e, p.t2)
+	}
+}
+
+// Removed implements the EvictionPolicy interface.
+func (p *arc) Removed(id string) {
+	if e, ok := p.entries[id]; ok && (e.list == p.t1 || e.list == p.t2) {
+		e.list.Remove(e.element)
+		delete(p.entries, id)
+	}
+}
+
+// Victim implements the EvictionPolicy interface.
+func (p *arc) Victim() (string, bool) {
+	var from, to *list.List
+	switch {
+	case p.t1.Len() > 0 && (p.t1.Len() > p.target || p.t2.Len() == 0):
+		from, to = p.t1, p.b1
+	case p.t2.Len() > 0:
+		from, to = p.t2, p.b2
+	default:
+		return "", false
+	}
+	id := from.Back().Value.(string)
+	p.move(id, p.entries[id], to)
+	p.trim()
+	return id, true
+}
+
+// capacity returns the largest number of resident entries.
+func (p *arc) capacity() int {
+	return maxInt(p.size, 1)
+}
+
+// push adds the ID at the front of the list.
+func (p *arc) push(id string, l *list.List) {
+	p.entries[id] = &arcEntry{l, l.PushFront(id)}
+}
+
+// move moves the ID to the front of the list.
+func (p *arc) move(id string, e *arcEntry, l *list.List) {
+	e.list.Remove(e.element)
+	p.push(id, l)
+}
+
+// trim limits the number of remembered evicted IDs.
+func (p *arc) trim() {
+	c := p.capacity()
+	for p.b1.Len() > 0 && p.t1.Len()+p.b1.Len() > c {
+		delete(p.entries, p.b1.Remove(p.b1.Back()).(string))
+	}
+	for p.b2.Len() > 0 && p.b1.Len()+p.b2.Len() > c {
+		delete(p.entries, p.b2.Remove(p.b2.Back()).(string))
+	}
+}
+
+// minInt returns the smaller int.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxInt returns the larger int.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// EOF
@@ -0,0 +1,347 @@
+// Tideland Go Library - Cache - Invalidation
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tideland/golib/errors"
+	"github.com/tideland/golib/identifier"
+	"github.com/tideland/golib/logger"
+	"github.com/tideland/golib/redis"
+)
+
+//--------------------
+// CONSTANTS
+//--------------------
+
+// Events of the invalidation.
+const (
+	eventDiscard = "discard"
+	eventClear   = "clear"
+)
+
+//--------------------
+// BUS
+//--------------------
+
+// Bus transports the invalidation messages between the
+// instances of a cache.
+type Bus interface {
+	// Publish sends the message to all receivers of the channel.
+	Publish(channel, message string) error
+
+	// Subscribe returns a receiver for the messages of the channel.
+	Subscribe(channel string) (Receiver, error)
+}
+
+// Receiver receives the messages of a channel.
+type Receiver interface {
+	// Receive waits for the next message.
+	Receive() (string, error)
+
+	// Close ends receiving, a waiting Receive returns
+	// an error.
+	Close() error
+}
+
+//--------------------
+// INVALIDATING CACHE
+//--------------------
+
+// invalidatingCache wraps a cache to broadcast and apply
+// discards and clears.
+type invalidatingCache struct {
+	Cache
+	origin   string
+	channel  string
+	bus      Bus
+	receiver Receiver
+	stopc    chan struct{}
+	donec    chan struct{}
+}
+
+// NewInvalidatingCache wraps the passed cache. Its discards and clears
+// are published on the bus in the channel "cache:invalidation:<id>",
+// so that other instances of the cache with the same ID discard or
+// clear too. In return the ones of the other instances are applied
+// to the cache. Own published events are ignored.
+//
+//     c, err := cache.New(cache.ID("templates"), cache.Loader(loader))
+//     ...
+//     ic, err := cache.NewInvalidatingCache(c, cache.NewRedisBus(db))
+func NewInvalidatingCache(c Cache, bus Bus) (Cache, error) {
+	ic := &invalidatingCache{
+		Cache:   c,
+		origin:  identifier.NewUUID().String(),
+		channel: identifier.Identifier("cache", "invalidation", c.Stats().ID),
+		bus:     bus,
+		stopc:   make(chan struct{}),
+		donec:   make(chan struct{}),
+	}
+	receiver, err := bus.Subscribe(ic.channel)
+	if err != nil {
+		return nil, errors.Annotate(err, ErrInvalidation, errorMessages, ic.channel)
+	}
+	ic.receiver = receiver
+	go ic.receive()
+	return ic, nil
+}
+
+// Discard implements the Cache interface.
+func (ic *invalidatingCache) Discard(id string) error {
+	if err := ic.Cache.Discard(id); err != nil {
+		return err
+	}
+	return ic.publish(eventDiscard, id)
+}
+
+// Clear implements the Cache interface.
+func (ic *invalidatingCache) Clear() error {
+	if err := ic.Cache.Clear(); err != nil {
+		return err
+	}
+	return ic.publish(eventClear, "")
+}
+
+// Stop implements the Cache interface.
+func (ic *invalidatingCache) Stop() error {
+	// Closing the receiver stops the receiving.
+	var errs []error
+	close(ic.stopc)
+	if err := ic.receiver.Close(); err != nil {
+		errs = append(errs, err)
+	} else {
+		select {
+		case <-ic.donec:
+		case <-time.After(5 * time.Second):
+			errs = append(errs, errors.New(ErrTimeout, errorMessages, "stopping invalidation"))
+		}
+	}
+	if err := ic.Cache.Stop(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errors.Collect(errs...)
+	}
+	return nil
+}
+
+// publish sends an event to the other instances.
+func (ic *invalidatingCache) publish(event, id string) error {
+	message := ic.origin + " " + event + " " + id
+	if err := ic.bus.Publish(ic.channel, message); err != nil {
+		return errors.Annotate(err, ErrInvalidation, errorMessages, ic.channel)
+	}
+	return nil
+}
+
+// receive applies the events of the other instances.
+func (ic *invalidatingCache) receive() {
+	defer close(ic.donec)
+	for {
+		message, err := ic.receiver.Receive()
+		if err != nil {
+			select {
+			case <-ic.stopc:
+			default:
+				logger.Errorf("cannot receive invalidation for %q: %v", ic.channel, err)
+			}
+			return
+		}
+		parts := strings.SplitN(message, " ", 3)
+		if len(parts) != 3 {
+			logger.Warningf("invalid invalidation for %q: %q", ic.channel, message)
+			continue
+		}
+		origin, event, id := parts[0], parts[1], parts[2]
+		if origin == ic.origin {
+			continue
+		}
+		switch event {
+		case eventDiscard:
+			err = ic.Cache.Discard(id)
+		case eventClear:
+			err = ic.Cache.Clear()
+		}
+		if err != nil {
+			logger.Errorf("cannot apply invalidation for %q: %v", ic.channel, err)
+		}
+	}
+}
+
+//--------------------
+// REDIS BUS
+//--------------------
+
+// redisBus implements the Bus interface using Redis.
+type redisBus struct {
+	db *redis.Database
+}
+
+// NewRedisBus returns a Bus using the publishing
+// and subscriptions of Redis.
+func NewRedisBus(db *redis.Database) Bus {
+	return &redisBus{
+		db: db,
+	}
+}
+
+// Publish implements the Bus interface.
+func (b *redisBus) Publish(channel, message string) error {
+	conn, err := b.db.Connection()
+	if err != nil {
+		return err
+	}
+	defer conn.Return()
+	_, err = conn.Do("publish", channel, message)
+	return err
+}
+
+// Subscribe implements the Bus interface.
+func (b *redisBus) Subscribe(channel string) (Receiver, error) {
+	sub, err := b.db.Subscription()
+	if err != nil {
+		return nil, err
+	}
+	if err = sub.Subscribe(channel); err != nil {
+		return nil, err
+	}
+	return &redisReceiver{
+		sub:     sub,
+		channel: channel,
+	}, nil
+}
+
+// redisReceiver implements the Receiver interface using Redis.
+type redisReceiver struct {
+	sub     *redis.Subscription
+	channel string
+	closed  bool
+}
+
+// Receive implements the Receiver interface. The subscription
+// is closed here after the unsubscribing has been confirmed, so
+// it's only read by one goroutine.
+func (r *redisReceiver) Receive() (string, error) {
+	for !r.closed {
+		pv, err := r.sub.Pop()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case pv.Kind == "message":
+			return pv.Value.String(), nil
+		case pv.Kind == "unsubscribe" && pv.Count == 0:
+			r.closed = true
+			if err = r.sub.Close(); err != nil {
+				return "", err
+			}
+		}
+	}
+	return "", errors.New(ErrInvalidation, errorMessages, r.channel)
+}
+
+// Close implements the Receiver interface. It unsubscribes,
+// the subscription is closed by Receive.
+func (r *redisReceiver) Close() error {
+	return r.sub.Unsubscribe(r.channel)
+}
+
+//--------------------
+// MEMORY BUS
+//--------------------
+
+// memoryBus implements the Bus interface in memory.
+type memoryBus struct {
+	mutex     sync.RWMutex
+	receivers map[string]map[*memoryReceiver]struct{}
+}
+
+// NewMemoryBus returns a Bus inside the process. It's a
+// stand-in for tests or for multiple caches inside one
+// process.
+func NewMemoryBus() Bus {
+	return &memoryBus{
+		receivers: make(map[string]map[*memoryReceiver]struct{}),
+	}
+}
+
+// Publish implements the Bus interface. The messages are sent
+// without holding the lock, so receivers not reading anymore
+// only block the publishing until they are closed.
+func (b *memoryBus) Publish(channel, message string) error {
+	b.mutex.RLock()
+	receivers := make([]*memoryReceiver, 0, len(b.receivers[channel]))
+	for r := range b.receivers[channel] {
+		receivers = append(receivers, r)
+	}
+	b.mutex.RUnlock()
+	for _, r := range receivers {
+		select {
+		case r.messagec <- message:
+		case <-r.closec:
+		}
+	}
+	return nil
+}
+
+// Subscribe implements the Bus interface.
+func (b *memoryBus) Subscribe(channel string) (Receiver, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	r := &memoryReceiver{
+		bus:      b,
+		channel:  channel,
+		messagec: make(chan string, 16),
+		closec:   make(chan struct{}),
+	}
+	if b.receivers[channel] == nil {
+		b.receivers[channel] = make(map[*memoryReceiver]struct{})
+	}
+	b.receivers[channel][r] = struct{}{}
+	return r, nil
+}
+
+// memoryReceiver implements the Receiver interface in memory.
+type memoryReceiver struct {
+	bus      *memoryBus
+	channel  string
+	messagec chan string
+	closec   chan struct{}
+	once     sync.Once
+}
+
+// Receive implements the Receiver interface.
+func (r *memoryReceiver) Receive() (string, error) {
+	select {
+	case message := <-r.messagec:
+		return message, nil
+	case <-r.closec:
+		return "", errors.New(ErrInvalidation, errorMessages, r.channel)
+	}
+}
+
+// Close implements the Receiver interface.
+func (r *memoryReceiver) Close() error {
+	r.bus.mutex.Lock()
+	defer r.bus.mutex.Unlock()
+	delete(r.bus.receivers[r.channel], r)
+	r.once.Do(func() {
+		close(r.closec)
+	})
+	return nil
+}
+
+// EOF
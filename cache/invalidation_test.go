@@ -0,0 +1,162 @@
+// Tideland Go Library - Cache - Unit Tests
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/cache"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestMemoryInvalidation tests the invalidation between
+// caches using the memory bus.
+func TestMemoryInvalidation(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	testInvalidation(assert, "memory-invalidation", cache.NewMemoryBus())
+}
+
+// TestRedisInvalidation tests the invalidation between caches
+// using Redis. It's skipped if no Redis server is available.
+func TestRedisInvalidation(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	db := openRedis(t)
+	defer db.Close()
+
+	testInvalidation(assert, "redis-invalidation", cache.NewRedisBus(db))
+}
+
+// TestMemoryBusBlockedReceiver tests that a receiver not reading
+// anymore doesn't block subscribing and closing.
+func TestMemoryBusBlockedReceiver(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	bus := cache.NewMemoryBus()
+	r, err := bus.Subscribe("blocked")
+	assert.Nil(err)
+
+	// Publish more messages than buffered.
+	donec := make(chan struct{})
+	go func() {
+		defer close(donec)
+		for i := 0; i < 32; i++ {
+			bus.Publish("blocked", "message")
+		}
+	}()
+	select {
+	case <-donec:
+		assert.Fail("publishing to a full receiver returned")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Subscribing and closing still work, closing
+	// ends the publishing.
+	other, err := bus.Subscribe("other")
+	assert.Nil(err)
+	err = other.Close()
+	assert.Nil(err)
+	err = r.Close()
+	assert.Nil(err)
+	select {
+	case <-donec:
+	case <-time.After(time.Second):
+		assert.Fail("publishing to a closed receiver blocked")
+	}
+	err = r.Close()
+	assert.Nil(err)
+
+	// Receiving from a closed receiver returns an error.
+	_, err = other.Receive()
+	assert.ErrorMatch(err, ".*cannot use invalidation channel 'other'.*")
+}
+
+// TestMemoryInvalidationStop tests that stopping an invalidating
+// cache doesn't publish to the other instances.
+func TestMemoryInvalidationStop(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	te := initEnvironment()
+	bus := cache.NewMemoryBus()
+	r, err := bus.Subscribe("cache:invalidation:memory-invalidation-stop")
+	assert.Nil(err)
+	defer r.Close()
+
+	c, err := cache.New(cache.ID("memory-invalidation-stop"), cache.Loader(te.loader))
+	assert.Nil(err)
+	ic, err := cache.NewInvalidatingCache(c, bus)
+	assert.Nil(err)
+	err = ic.Stop()
+	assert.Nil(err)
+
+	err = bus.Publish("cache:invalidation:memory-invalidation-stop", "marker")
+	assert.Nil(err)
+	message, err := r.Receive()
+	assert.Nil(err)
+	assert.Equal(message, "marker")
+}
+
+//--------------------
+// HELPERS
+//--------------------
+
+// testInvalidation runs the invalidation tests with the passed bus.
+func testInvalidation(assert audit.Assertion, id string, bus cache.Bus) {
+	te := initEnvironment()
+	newCache := func() cache.Cache {
+		c, err := cache.New(cache.ID(id), cache.Loader(te.loader))
+		assert.Nil(err)
+		ic, err := cache.NewInvalidatingCache(c, bus)
+		assert.Nil(err)
+		return ic
+	}
+	load := func(c cache.Cache) {
+		_, err := c.Load(idValidCacheable, time.Second)
+		assert.Nil(err)
+	}
+	emptied := func(c cache.Cache) func() bool {
+		return func() bool {
+			return c.Len() == 0
+		}
+	}
+	ca := newCache()
+	cb := newCache()
+
+	// Discard is applied to the other cache.
+	load(ca)
+	load(cb)
+	err := cb.Discard(idValidCacheable)
+	assert.Nil(err)
+	assert.Equal(cb.Len(), 0)
+	assert.Retry(emptied(ca), 100, 10*time.Millisecond)
+
+	// Clear is applied to the other cache, but not again
+	// to the own one.
+	load(ca)
+	load(cb)
+	err = ca.Clear()
+	assert.Nil(err)
+	load(ca)
+	assert.Retry(emptied(cb), 100, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(ca.Len(), 1)
+
+	err = ca.Stop()
+	assert.Nil(err)
+	err = cb.Stop()
+	assert.Nil(err)
+}
+
+// EOF
@@ -82,6 +82,12 @@ func (c *fileCacheable) Discard() error {
 	return nil
 }
 
+// Weight implements the Weigher interface. It's the size
+// of the cached data, files read directly weigh nothing.
+func (c *fileCacheable) Weight() int64 {
+	return int64(len(c.data))
+}
+
 // ReadCloser implements the FileCacheable interface.
 func (c *fileCacheable) ReadCloser() (io.ReadCloser, error) {
 	// Check if the file has to be returned directly because
@@ -97,8 +103,22 @@ func (c *fileCacheable) ReadCloser() (io.ReadCloser, error) {
 	return &fileBuffer{bytes.NewBuffer(c.data)}, nil
 }
 
+// IsFileNotExist checks if the error returned by the file
+// loader signals a not existing file. It can be used as
+// ErrorMatcher for negative caching.
+func IsFileNotExist(err error) bool {
+	for _, serr := range errors.Stack(err) {
+		if os.IsNotExist(serr) {
+			return true
+		}
+	}
+	return false
+}
+
 // NewFileLoader returns a CacheableLoader for files. It
-// starts at the given root directory.
+// starts at the given root directory. The IDs should be clean
+// paths relative to the root, e.g. "sub/file.txt", as returned
+// by filepath.Rel(). Only those are matched by WatchFiles.
 func NewFileLoader(root string, maxSize int64) CacheableLoader {
 	return func(name string) (Cacheable, error) {
 		fn := filepath.Join(root, name)
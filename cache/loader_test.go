@@ -15,6 +15,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/tideland/golib/audit"
 	"github.com/tideland/golib/cache"
@@ -73,6 +74,70 @@ func TestFileLoader(t *testing.T) {
 	}
 }
 
+// TestFileLoaderMaxWeight tests the eviction of files
+// by their size.
+func TestFileLoaderMaxWeight(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+
+	createFile(assert, td.String(), "fa", 1)
+	createFile(assert, td.String(), "fb", 2)
+	createFile(assert, td.String(), "fc", 3)
+	createFile(assert, td.String(), "fd", 4)
+
+	c, err := cache.New(cache.ID("file-max-weight"),
+		cache.Loader(cache.NewFileLoader(td.String(), int64(3*multiplier))),
+		cache.MaxWeight(int64(4*multiplier)))
+	assert.Nil(err)
+	defer c.Stop()
+
+	// Files fa and fb fit, fc needs the room of both.
+	for _, name := range []string{"fa", "fb"} {
+		_, err = c.Load(name, time.Second)
+		assert.Nil(err)
+	}
+	assert.Equal(c.Len(), 2)
+	_, err = c.Load("fc", time.Second)
+	assert.Nil(err)
+	assert.Equal(c.Len(), 1)
+
+	// File fa fits again, fd is too large for caching
+	// and so weighs nothing.
+	_, err = c.Load("fa", time.Second)
+	assert.Nil(err)
+	_, err = c.Load("fd", time.Second)
+	assert.Nil(err)
+	assert.Equal(c.Len(), 3)
+}
+
+// TestFileLoaderNotExist tests the negative caching
+// of not existing files.
+func TestFileLoaderNotExist(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+
+	createFile(assert, td.String(), "fa", 1)
+
+	c, err := cache.New(cache.ID("file-not-exist"),
+		cache.Loader(cache.NewFileLoader(td.String(), int64(3*multiplier))),
+		cache.NegativeTTL(time.Minute), cache.NegativeMatcher(cache.IsFileNotExist))
+	assert.Nil(err)
+	defer c.Stop()
+
+	for i := 0; i < 3; i++ {
+		_, err = c.Load("fx", time.Second)
+		assert.True(cache.IsFileNotExist(err))
+	}
+	_, err = c.Load("fa", time.Second)
+	assert.Nil(err)
+	stats := c.Stats()
+	assert.Equal(stats.LoadErrors, int64(1))
+	assert.Equal(stats.NegativeHits, int64(2))
+	assert.False(cache.IsFileNotExist(nil))
+}
+
 //--------------------
 // HEKPERS
 //--------------------
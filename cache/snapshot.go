@@ -0,0 +1,142 @@
+// Tideland Go Library - Cache - Snapshots
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tideland/golib/errors"
+	"github.com/tideland/golib/logger"
+)
+
+//--------------------
+// SNAPSHOT
+//--------------------
+
+// snapshot contains the settings for snapshots of a cache.
+type snapshot struct {
+	path      string
+	marshal   Marshaller
+	unmarshal Unmarshaller
+}
+
+// snapshotEntry is one Cacheable inside a snapshot file.
+type snapshotEntry struct {
+	ID       string    `json:"id"`
+	Loaded   time.Time `json:"loaded"`
+	LastUsed time.Time `json:"last_used"`
+	Data     []byte    `json:"data"`
+}
+
+// writeSnapshot writes the loaded Cacheables into the snapshot
+// file. It's written to a temporary file first and then renamed,
+// so that a crash doesn't leave a broken snapshot.
+func (c *cache) writeSnapshot() error {
+	entries := []snapshotEntry{}
+	for id, b := range c.buckets {
+		if b.cacheable == nil {
+			continue
+		}
+		data, err := c.snapshot.marshal(b.cacheable)
+		if err != nil {
+			logger.Warningf("cannot marshal %q for snapshot: %v", id, err)
+			continue
+		}
+		entries = append(entries, snapshotEntry{
+			ID:       id,
+			Loaded:   b.loaded,
+			LastUsed: b.lastUsed,
+			Data:     data,
+		})
+	}
+	// Most recently used first, for restoring them
+	// with priority.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return errors.Annotate(err, ErrSnapshot, errorMessages, c.snapshot.path)
+	}
+	dir, file := filepath.Split(c.snapshot.path)
+	tmp, err := ioutil.TempFile(dir, file+".tmp")
+	if err != nil {
+		return errors.Annotate(err, ErrSnapshot, errorMessages, c.snapshot.path)
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.snapshot.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return errors.Annotate(err, ErrSnapshot, errorMessages, c.snapshot.path)
+	}
+	return nil
+}
+
+// readSnapshot restores the Cacheables of the snapshot file. Outdated
+// ones and those exceeding the limits of the cache are discarded. A
+// missing file is fine, other errors are only logged.
+func (c *cache) readSnapshot() {
+	data, err := ioutil.ReadFile(c.snapshot.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warningf("cannot read snapshot %q: %v", c.snapshot.path, err)
+		}
+		return
+	}
+	entries := []snapshotEntry{}
+	if err = json.Unmarshal(data, &entries); err != nil {
+		logger.Warningf("cannot read snapshot %q: %v", c.snapshot.path, err)
+		return
+	}
+	restored := []string{}
+	for _, entry := range entries {
+		cacheable, err := c.snapshot.unmarshal(entry.ID, entry.Data)
+		if err != nil {
+			logger.Warningf("cannot unmarshal %q from snapshot: %v", entry.ID, err)
+			continue
+		}
+		weight := weightOf(cacheable)
+		outdated, err := cacheable.IsOutdated()
+		if err != nil || outdated || c.exceeds(1, weight) {
+			if err = cacheable.Discard(); err != nil {
+				logger.Warningf("cannot discard %q from snapshot: %v", entry.ID, err)
+			}
+			continue
+		}
+		c.buckets[entry.ID] = &bucket{
+			cacheable: cacheable,
+			status:    statusLoaded,
+			loaded:    entry.Loaded,
+			lastUsed:  c.clock.Now(),
+			weight:    weight,
+		}
+		c.entries++
+		c.weight += weight
+		restored = append(restored, entry.ID)
+	}
+	// Tell the policy in order of usage.
+	for i := len(restored) - 1; i >= 0; i-- {
+		c.policy.Added(restored[i])
+	}
+}
+
+// EOF
@@ -0,0 +1,124 @@
+// Tideland Go Library - Cache - Unit Tests
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/cache"
+	"github.com/tideland/golib/timex"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestSnapshot tests writing a snapshot when stopping
+// and restoring it when creating a cache.
+func TestSnapshot(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+	te := initEnvironment()
+	path := filepath.Join(td.String(), "cache.snapshot")
+	marshal, unmarshal := snapshotMarshalling(te)
+
+	ca, err := cache.New(cache.ID("snapshot-a"), cache.Loader(te.loader),
+		cache.Snapshot(path, marshal, unmarshal))
+	assert.Nil(err)
+	for _, id := range []string{idValidCacheable, idErrorDuringCheck, fmt.Sprintf(idEviction, 0)} {
+		_, err = ca.Load(id, time.Second)
+		assert.Nil(err)
+	}
+	err = ca.Stop()
+	assert.Nil(err)
+	_, err = os.Stat(path)
+	assert.Nil(err)
+
+	// Restore without the one failing the outdated check.
+	cb, err := cache.New(cache.ID("snapshot-b"), cache.Loader(te.loader),
+		cache.Snapshot(path, marshal, unmarshal))
+	assert.Nil(err)
+	defer cb.Stop()
+	assert.Equal(cb.Len(), 2)
+	cacheable, err := cb.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+	assert.Equal(cacheable.ID(), idValidCacheable)
+	stats := cb.Stats()
+	assert.Equal(stats.Hits, int64(1))
+	assert.Equal(stats.Loads, int64(0))
+
+	// Restore limited by the maximum entries.
+	cc, err := cache.New(cache.ID("snapshot-c"), cache.Loader(te.loader),
+		cache.Snapshot(path, marshal, unmarshal), cache.MaxEntries(1))
+	assert.Nil(err)
+	defer cc.Stop()
+	assert.Equal(cc.Len(), 1)
+
+	// Broken snapshots lead to an empty cache.
+	td.Populate(audit.FileTree{"broken.snapshot": "{broken"})
+	cd, err := cache.New(cache.ID("snapshot-d"), cache.Loader(te.loader),
+		cache.Snapshot(filepath.Join(td.String(), "broken.snapshot"), marshal, unmarshal))
+	assert.Nil(err)
+	defer cd.Stop()
+	assert.Equal(cd.Len(), 0)
+}
+
+// TestSnapshotInterval tests writing snapshots in intervals.
+func TestSnapshotInterval(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+	te := initEnvironment()
+	clock := timex.NewFakeClock(time.Now())
+	path := filepath.Join(td.String(), "cache.snapshot")
+	marshal, unmarshal := snapshotMarshalling(te)
+
+	c, err := cache.New(cache.ID("snapshot-interval"), cache.Loader(te.loader),
+		cache.Clock(clock), cache.Snapshot(path, marshal, unmarshal),
+		cache.SnapshotInterval(time.Minute))
+	assert.Nil(err)
+	defer c.Stop()
+	_, err = c.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+
+	_, err = os.Stat(path)
+	assert.True(os.IsNotExist(err))
+	clock.Advance(time.Minute)
+	assert.Retry(func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, 100, 10*time.Millisecond)
+}
+
+//--------------------
+// HELPERS
+//--------------------
+
+// snapshotMarshalling returns the marshaller and unmarshaller
+// for testCacheables.
+func snapshotMarshalling(te *testEnvironment) (cache.Marshaller, cache.Unmarshaller) {
+	marshal := func(cacheable cache.Cacheable) ([]byte, error) {
+		return []byte(cacheable.ID()), nil
+	}
+	unmarshal := func(id string, data []byte) (cache.Cacheable, error) {
+		return &testCacheable{te: te, id: string(data)}, nil
+	}
+	return marshal, unmarshal
+}
+
+// EOF
@@ -0,0 +1,102 @@
+// Tideland Go Library - Cache - Statistics
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"time"
+
+	"github.com/tideland/golib/identifier"
+	"github.com/tideland/golib/monitoring"
+)
+
+//--------------------
+// STATS
+//--------------------
+
+// Stats contains the usage statistics of a Cache. A hit is
+// a lookup answered by a loaded Cacheable, even if it's refreshed
+// in the background. A miss is a lookup needing a load or waiting
+// for it. A negative hit is a lookup answered by a cached error of
+// the loader. An outdated reload is a lookup answered by an outdated
+// Cacheable, which is reloaded then. The load times are measured for
+// successful and failed loads.
+type Stats struct {
+	ID              string
+	Len             int
+	Weight          int64
+	Hits            int64
+	Misses          int64
+	Loads           int64
+	LoadErrors      int64
+	NegativeHits    int64
+	OutdatedReloads int64
+	Refreshes       int64
+	Evictions       int64
+	MinLoadTime     time.Duration
+	MaxLoadTime     time.Duration
+	AvgLoadTime     time.Duration
+}
+
+// Names of the stay-set variables and the measuring
+// point if monitoring is switched on.
+const (
+	monitorHits            = "hits"
+	monitorMisses          = "misses"
+	monitorLoads           = "loads"
+	monitorLoadErrors      = "load-errors"
+	monitorNegativeHits    = "negative-hits"
+	monitorOutdatedReloads = "outdated-reloads"
+	monitorRefreshes       = "refreshes"
+	monitorEvictions       = "evictions"
+	monitorLoad            = "load"
+)
+
+// monitorID returns the monitoring ID for the cache.
+func (c *cache) monitorID(name string) string {
+	return identifier.JoinedIdentifier("cache", c.id, name)
+}
+
+// count increments the counter and the according stay-set
+// variable if monitoring is switched on.
+func (c *cache) count(counter *int64, name string) {
+	*counter++
+	if c.monitoring {
+		monitoring.IncrVariable(c.monitorID(name))
+	}
+}
+
+// loadTime adds the duration of a load to the statistics.
+func (c *cache) loadTime(d time.Duration) {
+	if c.loadCount == 0 || d < c.stats.MinLoadTime {
+		c.stats.MinLoadTime = d
+	}
+	if d > c.stats.MaxLoadTime {
+		c.stats.MaxLoadTime = d
+	}
+	c.loadCount++
+	c.loadTotal += d
+	c.stats.AvgLoadTime = c.loadTotal / time.Duration(c.loadCount)
+}
+
+// beginMeasuring starts measuring a load if monitoring
+// is switched on. It returns the function to end it.
+func (c *cache) beginMeasuring() func() {
+	if !c.monitoring {
+		return func() {}
+	}
+	m := monitoring.BeginMeasuring(c.monitorID(monitorLoad))
+	return func() {
+		m.EndMeasuring()
+	}
+}
+
+// EOF
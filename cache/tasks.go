@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/tideland/golib/errors"
+	"github.com/tideland/golib/logger"
 )
 
 //--------------------
@@ -25,38 +26,85 @@ import (
 type task func(c *cache) error
 
 // failedTask notifies the cache that a loading failed.
-func failedTask(id string, err error) task {
+func failedTask(id string, err error, d time.Duration) task {
 	return func(c *cache) error {
+		c.count(&c.stats.LoadErrors, monitorLoadErrors)
+		c.loadTime(d)
 		// Check for discarded Cacheable first.
-		if c.buckets[id] == nil {
+		b := c.buckets[id]
+		if b == nil {
 			return nil
 		}
-		// Notify all waiters.
-		for _, waiter := range c.buckets[id].waiters {
+		// Notify all waiters, during refreshing these are the
+		// ones for whom the current Cacheable is too stale.
+		for _, waiter := range b.waiters {
 			waiter <- func() (Cacheable, error) {
 				return nil, errors.Annotate(err, ErrLoading, errorMessages, id)
 			}
 		}
-		delete(c.buckets, id)
+		b.waiters = nil
+		// Keep the current Cacheable if refreshing failed, so
+		// it can be retried with the next lookup.
+		if b.refreshing {
+			b.status = statusLoaded
+			b.refreshing = false
+			return nil
+		}
+		c.remove(id)
+		// Remember the error if wanted.
+		if c.negativeTTL > 0 && (c.negativeMatcher == nil || c.negativeMatcher(err)) {
+			now := c.clock.Now()
+			c.buckets[id] = &bucket{
+				status:   statusFailed,
+				loaded:   now,
+				lastUsed: now,
+				err:      err,
+			}
+		}
 		return nil
 	}
 }
 
 // successTask notifies the cache that a loading succeeded.
-func successTask(id string, cacheable Cacheable) task {
+func successTask(id string, cacheable Cacheable, d time.Duration) task {
 	return func(c *cache) error {
-		// Check for discarded Cacheable first.
+		c.count(&c.stats.Loads, monitorLoads)
+		c.loadTime(d)
+		// Check for discarded Cacheable first. Nobody
+		// waits for the new one, so discard it too.
 		if c.buckets[id] == nil {
+			if err := cacheable.Discard(); err != nil {
+				logger.Warningf("cache %q cannot discard %q loaded after discarding: %v", c.id, id, err)
+			}
 			return nil
 		}
-		// Set bucket values.
+		// Forget and discard a reloaded Cacheable and make
+		// room for the new one.
 		b := c.buckets[id]
+		if b.cacheable != nil {
+			c.entries--
+			c.weight -= b.weight
+			c.policy.Removed(id)
+			if b.cacheable != cacheable {
+				if err := b.cacheable.Discard(); err != nil {
+					logger.Warningf("cache %q cannot discard reloaded %q: %v", c.id, id, err)
+				}
+			}
+		}
+		weight := weightOf(cacheable)
+		c.evict(1, weight)
+		// Set bucket values.
 		b.cacheable = cacheable
 		b.status = statusLoaded
-		b.loaded = time.Now()
+		b.loaded = c.clock.Now()
 		b.lastUsed = b.loaded
+		b.weight = weight
+		b.refreshing = false
+		c.entries++
+		c.weight += weight
+		c.policy.Added(id)
 		// Notify all waiters.
-		for _, waiter := range c.buckets[id].waiters {
+		for _, waiter := range b.waiters {
 			waiter <- func() (Cacheable, error) {
 				return cacheable, nil
 			}
@@ -68,11 +116,15 @@ func successTask(id string, cacheable Cacheable) task {
 
 // loading is the asynchronous loading function.
 func loading(c *cache, id string) {
+	end := c.beginMeasuring()
+	start := time.Now()
 	cacheable, err := c.load(id)
+	d := time.Since(start)
+	end()
 	if err != nil {
-		c.taskc <- failedTask(id, err)
+		c.taskc <- failedTask(id, err, d)
 	} else {
-		c.taskc <- successTask(id, cacheable)
+		c.taskc <- successTask(id, cacheable, d)
 	}
 }
 
@@ -83,14 +135,42 @@ func lookupTask(id string, responsec responder) task {
 		switch {
 		case !ok:
 			// ID is unknown.
+			c.count(&c.stats.Misses, monitorMisses)
 			c.buckets[id] = &bucket{
 				status:  statusLoading,
 				waiters: []responder{responsec},
 			}
 			go loading(c, id)
+		case ok && b.status == statusLoading && b.refreshing && !c.tooStale(b):
+			// ID is known and Cacheable is refreshed in the background.
+			c.count(&c.stats.Hits, monitorHits)
+			b.lastUsed = c.clock.Now()
+			c.policy.Accessed(id)
+			cacheable := b.cacheable
+			responsec <- func() (Cacheable, error) {
+				return cacheable, nil
+			}
 		case ok && b.status == statusLoading:
 			// ID is known but Cacheable is not yet retrieved.
+			c.count(&c.stats.Misses, monitorMisses)
 			b.waiters = append(b.waiters, responsec)
+		case ok && b.status == statusFailed:
+			if c.clock.Now().Sub(b.loaded) < c.negativeTTL {
+				// ID is known and loading failed recently.
+				c.count(&c.stats.NegativeHits, monitorNegativeHits)
+				b.lastUsed = c.clock.Now()
+				err := b.err
+				responsec <- func() (Cacheable, error) {
+					return nil, errors.Annotate(err, ErrLoading, errorMessages, id)
+				}
+				return nil
+			}
+			// Loading failed too long ago, so retry.
+			c.count(&c.stats.Misses, monitorMisses)
+			b.status = statusLoading
+			b.err = nil
+			b.waiters = []responder{responsec}
+			go loading(c, id)
 		case ok && b.status == statusLoaded:
 			// ID is known and Cacheable is loaded.
 			outdated, err := b.cacheable.IsOutdated()
@@ -99,25 +179,64 @@ func lookupTask(id string, responsec responder) task {
 				responsec <- func() (Cacheable, error) {
 					return nil, errors.Annotate(err, ErrCheckOutdated, errorMessages, id)
 				}
-				delete(c.buckets, id)
+				c.remove(id)
+				return nil
+			}
+			if c.tooStale(b) {
+				// Expired too long ago, so wait for reload.
+				c.count(&c.stats.Misses, monitorMisses)
+				b.status = statusLoading
+				b.waiters = []responder{responsec}
+				go loading(c, id)
 				return nil
 			}
-			if outdated {
-				// Outdated, so reload.
-				c.buckets[id].status = statusLoading
-				c.buckets[id].waiters = []responder{responsec}
+			switch {
+			case outdated:
+				// Outdated, so return it a last time and reload.
+				c.count(&c.stats.OutdatedReloads, monitorOutdatedReloads)
+				b.status = statusLoading
+				go loading(c, id)
+			case c.refreshable(b):
+				// Expires soon or recently expired, so refresh
+				// in the background.
+				c.count(&c.stats.Refreshes, monitorRefreshes)
+				c.count(&c.stats.Hits, monitorHits)
+				b.status = statusLoading
+				b.refreshing = true
 				go loading(c, id)
+			default:
+				// Everything fine.
+				c.count(&c.stats.Hits, monitorHits)
 			}
-			// Everything fine.
-			b.lastUsed = time.Now()
+			b.lastUsed = c.clock.Now()
+			c.policy.Accessed(id)
+			cacheable := b.cacheable
 			responsec <- func() (Cacheable, error) {
-				return b.cacheable, nil
+				return cacheable, nil
 			}
 		}
 		return nil
 	}
 }
 
+// refreshable checks if the Cacheable of the bucket expires
+// soon or has expired and shall be refreshed in the background.
+func (c *cache) refreshable(b *bucket) bool {
+	if c.maxAge <= 0 {
+		return false
+	}
+	return c.clock.Now().Sub(b.loaded) >= c.maxAge-c.refreshAhead
+}
+
+// tooStale checks if the Cacheable of the bucket expired too
+// long ago to be returned while reloading.
+func (c *cache) tooStale(b *bucket) bool {
+	if c.maxAge <= 0 {
+		return false
+	}
+	return c.clock.Now().Sub(b.loaded) >= c.maxAge+c.maxStale
+}
+
 // discardTask returns the task for discarding a Cacheable.
 func discardTask(id string, responsec responder) task {
 	return func(c *cache) error {
@@ -140,7 +259,7 @@ func discardTask(id string, responsec responder) task {
 				return nil, errors.New(ErrDiscardedWhileLoading, errorMessages, id)
 			}
 		}
-		delete(c.buckets, id)
+		c.remove(id)
 		if err != nil {
 			err = errors.Annotate(err, ErrDiscard, errorMessages, id)
 		}
@@ -151,25 +270,38 @@ func discardTask(id string, responsec responder) task {
 	}
 }
 
+// statsTask returns the task to retrieve the statistics.
+func statsTask(statsc chan Stats) task {
+	return func(c *cache) error {
+		stats := c.stats
+		stats.ID = c.id
+		stats.Len = len(c.buckets)
+		stats.Weight = c.weight
+		statsc <- stats
+		return nil
+	}
+}
+
 // clearTask returns the task to clear the cache.
 func clearTask(responsec responder) task {
 	return func(c *cache) error {
 		var errs []error
 		for id, bucket := range c.buckets {
-			switch bucket.status {
-			case statusLoading:
-				for _, waiter := range bucket.waiters {
-					waiter <- func() (Cacheable, error) {
-						return nil, errors.New(ErrDiscardedWhileLoading, errorMessages, id)
-					}
-				}
-			case statusLoaded:
+			// Refreshing or revalidating buckets are loading
+			// but still contain their current Cacheable.
+			if bucket.cacheable != nil {
 				if err := bucket.cacheable.Discard(); err != nil {
 					errs = append(errs, err)
 				}
 			}
+			id := id
+			for _, waiter := range bucket.waiters {
+				waiter <- func() (Cacheable, error) {
+					return nil, errors.New(ErrDiscardedWhileLoading, errorMessages, id)
+				}
+			}
+			c.remove(id)
 		}
-		c.buckets = make(map[string]*bucket)
 		var err error
 		if len(errs) > 0 {
 			err = errors.Collect(errs...)
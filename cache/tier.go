@@ -0,0 +1,176 @@
+// Tideland Go Library - Cache - Tiers
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tideland/golib/errors"
+	"github.com/tideland/golib/logger"
+	"github.com/tideland/golib/redis"
+)
+
+//--------------------
+// TIER
+//--------------------
+
+// Tier is a second level for caches storing serialized Cacheables,
+// e.g. to share them between multiple instances of a service.
+type Tier interface {
+	// Get returns the data stored for the ID. It's nil
+	// if there's none.
+	Get(id string) ([]byte, error)
+
+	// Set stores the data for the ID.
+	Set(id string, data []byte) error
+}
+
+// Marshaller serializes a Cacheable for a Tier.
+type Marshaller func(cacheable Cacheable) ([]byte, error)
+
+// Unmarshaller deserializes a Cacheable read from a Tier.
+type Unmarshaller func(id string, data []byte) (Cacheable, error)
+
+// NewTierLoader returns a CacheableLoader first looking into the
+// tier. Only if the Cacheable isn't found there it calls the passed
+// loader and stores the result in the tier. Errors of the tier or
+// during (un)marshalling are logged, the loader is used then.
+//
+//     tier := cache.NewRedisTier(db, "my-service", 10*time.Minute)
+//     loader := cache.NewTierLoader(tier, marshal, unmarshal, myLoader)
+//     c, err := cache.New(cache.Loader(loader))
+func NewTierLoader(tier Tier, marshal Marshaller, unmarshal Unmarshaller, loader CacheableLoader) CacheableLoader {
+	return func(id string) (Cacheable, error) {
+		data, err := tier.Get(id)
+		if err != nil {
+			logger.Warningf("cannot get %q from cache tier: %v", id, err)
+		}
+		if data != nil {
+			cacheable, err := unmarshal(id, data)
+			if err == nil {
+				return cacheable, nil
+			}
+			logger.Warningf("cannot unmarshal %q from cache tier: %v", id, err)
+		}
+		cacheable, err := loader(id)
+		if err != nil {
+			return nil, err
+		}
+		data, err = marshal(cacheable)
+		if err != nil {
+			logger.Warningf("cannot marshal %q for cache tier: %v", id, err)
+			return cacheable, nil
+		}
+		if err = tier.Set(id, data); err != nil {
+			logger.Warningf("cannot set %q in cache tier: %v", id, err)
+		}
+		return cacheable, nil
+	}
+}
+
+//--------------------
+// REDIS TIER
+//--------------------
+
+// redisTier implements the Tier interface using Redis.
+type redisTier struct {
+	db     *redis.Database
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisTier returns a Tier storing the data in Redis. The keys
+// are the IDs with the prefix and a colon, the ttl is set as expiry
+// of the keys. A ttl of 0 lets the keys never expire.
+func NewRedisTier(db *redis.Database, prefix string, ttl time.Duration) Tier {
+	return &redisTier{
+		db:     db,
+		prefix: prefix,
+		ttl:    ttl,
+	}
+}
+
+// Get implements the Tier interface.
+func (t *redisTier) Get(id string) ([]byte, error) {
+	conn, err := t.db.Connection()
+	if err != nil {
+		return nil, errors.Annotate(err, ErrTier, errorMessages, id)
+	}
+	defer conn.Return()
+	value, err := conn.DoValue("get", t.key(id))
+	if err != nil {
+		return nil, errors.Annotate(err, ErrTier, errorMessages, id)
+	}
+	if value.IsNil() {
+		return nil, nil
+	}
+	return value.Bytes(), nil
+}
+
+// Set implements the Tier interface.
+func (t *redisTier) Set(id string, data []byte) error {
+	conn, err := t.db.Connection()
+	if err != nil {
+		return errors.Annotate(err, ErrTier, errorMessages, id)
+	}
+	defer conn.Return()
+	args := []interface{}{t.key(id), data}
+	if t.ttl > 0 {
+		args = append(args, "px", int64(t.ttl/time.Millisecond))
+	}
+	if _, err = conn.DoOK("set", args...); err != nil {
+		return errors.Annotate(err, ErrTier, errorMessages, id)
+	}
+	return nil
+}
+
+// key returns the Redis key for the ID.
+func (t *redisTier) key(id string) string {
+	return t.prefix + ":" + id
+}
+
+//--------------------
+// MEMORY TIER
+//--------------------
+
+// memoryTier implements the Tier interface in memory.
+type memoryTier struct {
+	mutex sync.RWMutex
+	data  map[string][]byte
+}
+
+// NewMemoryTier returns a Tier keeping the data in memory
+// without expiry. It's a stand-in for tests or for multiple
+// caches inside one process.
+func NewMemoryTier() Tier {
+	return &memoryTier{
+		data: make(map[string][]byte),
+	}
+}
+
+// Get implements the Tier interface.
+func (t *memoryTier) Get(id string) ([]byte, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.data[id], nil
+}
+
+// Set implements the Tier interface.
+func (t *memoryTier) Set(id string, data []byte) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.data[id] = data
+	return nil
+}
+
+// EOF
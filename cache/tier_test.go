@@ -0,0 +1,138 @@
+// Tideland Go Library - Cache - Unit Tests
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/cache"
+	"github.com/tideland/golib/errors"
+	"github.com/tideland/golib/identifier"
+	"github.com/tideland/golib/redis"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestTierLoader tests the sharing of Cacheables between
+// caches using a tier.
+func TestTierLoader(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	tl := newTierLoader(cache.NewMemoryTier())
+
+	ca, err := cache.New(cache.ID("tier-a"), cache.Loader(tl.loader))
+	assert.Nil(err)
+	defer ca.Stop()
+	cb, err := cache.New(cache.ID("tier-b"), cache.Loader(tl.loader))
+	assert.Nil(err)
+	defer cb.Stop()
+
+	// First cache loads, second one gets it from the tier.
+	cacheable, err := ca.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+	assert.Equal(cacheable.ID(), idValidCacheable)
+	cacheable, err = cb.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+	assert.Equal(cacheable.ID(), idValidCacheable)
+	assert.Equal(tl.loads, 1)
+
+	// Errors are not stored.
+	for i := 0; i < 2; i++ {
+		_, err = cb.Load(idErrorDuringLoading, time.Second)
+		assert.ErrorMatch(err, ".*error during loading.*")
+	}
+	assert.Equal(tl.loads, 3)
+
+	// Invalid data in the tier leads to loading.
+	tier := cache.NewMemoryTier()
+	err = tier.Set(idValidCacheable, []byte("invalid"))
+	assert.Nil(err)
+	tl = newTierLoader(tier)
+	cacheable, err = tl.loader(idValidCacheable)
+	assert.Nil(err)
+	assert.Equal(cacheable.ID(), idValidCacheable)
+	assert.Equal(tl.loads, 1)
+	data, err := tier.Get(idValidCacheable)
+	assert.Nil(err)
+	assert.Equal(string(data), "tier:"+idValidCacheable)
+}
+
+// TestRedisTier tests the tier using Redis. It's skipped
+// if no Redis server is available.
+func TestRedisTier(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	db := openRedis(t)
+	defer db.Close()
+
+	prefix := identifier.Identifier("cache", "test", identifier.NewUUID())
+	tier := cache.NewRedisTier(db, prefix, time.Minute)
+	data, err := tier.Get(idValidCacheable)
+	assert.Nil(err)
+	assert.Nil(data)
+	err = tier.Set(idValidCacheable, []byte("tier:"+idValidCacheable))
+	assert.Nil(err)
+	data, err = tier.Get(idValidCacheable)
+	assert.Nil(err)
+	assert.Equal(string(data), "tier:"+idValidCacheable)
+}
+
+//--------------------
+// HELPERS
+//--------------------
+
+// openRedis opens the local Redis database. The test
+// is skipped if no server is available.
+func openRedis(t *testing.T) *redis.Database {
+	db, err := redis.Open(redis.UnixConnection("", 0), redis.Index(0, ""))
+	if err == nil {
+		var conn *redis.Connection
+		if conn, err = db.Connection(); err == nil {
+			conn.Return()
+		}
+	}
+	if err != nil {
+		t.Skipf("no redis server available: %v", err)
+	}
+	return db
+}
+
+// tierLoader counts the loads behind a tier.
+type tierLoader struct {
+	loads  int
+	loader cache.CacheableLoader
+}
+
+// newTierLoader creates a tier loader for the passed tier.
+func newTierLoader(tier cache.Tier) *tierLoader {
+	te := initEnvironment()
+	tl := &tierLoader{}
+	load := func(id string) (cache.Cacheable, error) {
+		tl.loads++
+		return te.loader(id)
+	}
+	marshal := func(cacheable cache.Cacheable) ([]byte, error) {
+		return []byte("tier:" + cacheable.ID()), nil
+	}
+	unmarshal := func(id string, data []byte) (cache.Cacheable, error) {
+		if string(data) != "tier:"+id {
+			return nil, errors.New(errLoading, errorMessages)
+		}
+		return &testCacheable{te: te, id: id}, nil
+	}
+	tl.loader = cache.NewTierLoader(tier, marshal, unmarshal, load)
+	return tl
+}
+
+// EOF
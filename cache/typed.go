@@ -0,0 +1,259 @@
+// Tideland Go Library - Cache - Typed
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tideland/golib/errors"
+)
+
+//--------------------
+// VALUE INTERFACES
+//--------------------
+
+// Outdater can be implemented by values of a TypedCache to
+// tell if they are outdated, see Cacheable.IsOutdated().
+type Outdater interface {
+	IsOutdated() (bool, error)
+}
+
+// Discarder can be implemented by values of a TypedCache to
+// clean up when they are removed, see Cacheable.Discard().
+type Discarder interface {
+	Discard() error
+}
+
+//--------------------
+// TYPED CACHE
+//--------------------
+
+// TypedLoader loads the value for a key of a TypedCache.
+type TypedLoader[K comparable, V any] func(key K) (V, error)
+
+// TypedCache loads and returns values of type V by keys of type K
+// and caches them in memory. It's backed by a Cache, so it behaves
+// the same way. Values may implement Outdater, Discarder, and
+// Weigher to control their caching.
+type TypedCache[K comparable, V any] interface {
+	// Load returns a value from memory or source.
+	Load(key K, timeout time.Duration) (V, error)
+
+	// Discard explicitly removes a value from the TypedCache.
+	// Normally done automatically.
+	Discard(key K) error
+
+	// Clear empties the TypedCache.
+	Clear() error
+
+	// Len returns the number of entries in the TypedCache.
+	Len() int
+
+	// Stats returns the usage statistics of the TypedCache.
+	Stats() Stats
+
+	// Stop tells the TypedCache to stop working.
+	Stop() error
+}
+
+// typedKey is a key of a TypedCache. It's referenced by running
+// Load calls and by the loaded value, so it's kept until all
+// of them are done.
+type typedKey[K comparable] struct {
+	key  K
+	refs int
+}
+
+// typedCache implements the TypedCache interface.
+type typedCache[K comparable, V any] struct {
+	mutex sync.Mutex
+	keys  map[string]*typedKey[K]
+	load  TypedLoader[K, V]
+	cache Cache
+}
+
+// NewTypedCache creates a new typed cache using the loader. The
+// options are those of New(), only the Loader option is ignored.
+// Cacheables restored by the Snapshot option are not loaded by the
+// typed loader, so loading them returns an error.
+//
+//     c, err := cache.NewTypedCache(func(id int) (*User, error) {
+//         return users.Find(id)
+//     }, cache.ID("users"), cache.TTL(time.Hour))
+//     ...
+//     user, err := c.Load(4711, time.Second)
+func NewTypedCache[K comparable, V any](load TypedLoader[K, V], options ...Option) (TypedCache[K, V], error) {
+	if load == nil {
+		return nil, errors.New(ErrNoLoader, errorMessages)
+	}
+	tc := &typedCache[K, V]{
+		keys: make(map[string]*typedKey[K]),
+		load: load,
+	}
+	options = append(options, Loader(tc.loader))
+	c, err := New(options...)
+	if err != nil {
+		return nil, err
+	}
+	tc.cache = c
+	return tc, nil
+}
+
+// Load implements the TypedCache interface.
+func (tc *typedCache[K, V]) Load(key K, timeout time.Duration) (V, error) {
+	id := typedID(key)
+	tc.acquire(id, key)
+	defer tc.release(id)
+	var zero V
+	cacheable, err := tc.cache.Load(id, timeout)
+	if err != nil {
+		return zero, err
+	}
+	tcv, ok := cacheable.(*typedCacheable[K, V])
+	if !ok {
+		return zero, errors.New(ErrIllegalCacheable, errorMessages, id)
+	}
+	return tcv.value, nil
+}
+
+// Discard implements the TypedCache interface.
+func (tc *typedCache[K, V]) Discard(key K) error {
+	return tc.cache.Discard(typedID(key))
+}
+
+// Clear implements the TypedCache interface.
+func (tc *typedCache[K, V]) Clear() error {
+	return tc.cache.Clear()
+}
+
+// Len implements the TypedCache interface.
+func (tc *typedCache[K, V]) Len() int {
+	return tc.cache.Len()
+}
+
+// Stats implements the TypedCache interface.
+func (tc *typedCache[K, V]) Stats() Stats {
+	return tc.cache.Stats()
+}
+
+// Stop implements the TypedCache interface.
+func (tc *typedCache[K, V]) Stop() error {
+	return tc.cache.Stop()
+}
+
+// loader is the CacheableLoader of the backing cache.
+func (tc *typedCache[K, V]) loader(id string) (Cacheable, error) {
+	tc.mutex.Lock()
+	tk, ok := tc.keys[id]
+	if ok {
+		// Reference for the loaded value.
+		tk.refs++
+	}
+	tc.mutex.Unlock()
+	if !ok {
+		return nil, errors.New(ErrLoading, errorMessages, id)
+	}
+	value, err := tc.load(tk.key)
+	if err != nil {
+		tc.release(id)
+		return nil, err
+	}
+	return &typedCacheable[K, V]{
+		id:    id,
+		value: value,
+		cache: tc,
+	}, nil
+}
+
+// acquire adds a reference to the key.
+func (tc *typedCache[K, V]) acquire(id string, key K) {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+	tk, ok := tc.keys[id]
+	if !ok {
+		tk = &typedKey[K]{key: key}
+		tc.keys[id] = tk
+	}
+	tk.refs++
+}
+
+// release removes a reference to the key and forgets
+// it if it's not referenced anymore.
+func (tc *typedCache[K, V]) release(id string) {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+	tk, ok := tc.keys[id]
+	if !ok {
+		return
+	}
+	tk.refs--
+	if tk.refs <= 0 {
+		delete(tc.keys, id)
+	}
+}
+
+// typedID returns the ID of a key in the backing cache. String
+// keys are used directly, all others are prefixed with their
+// dynamic type, so that e.g. "1" and 1 of an interface type
+// don't collide.
+func typedID[K comparable](key K) string {
+	var zero K
+	if _, ok := interface{}(zero).(string); ok {
+		return interface{}(key).(string)
+	}
+	return fmt.Sprintf("%T:%#v", key, key)
+}
+
+//--------------------
+// TYPED CACHEABLE
+//--------------------
+
+// typedCacheable wraps the values of a TypedCache.
+type typedCacheable[K comparable, V any] struct {
+	id    string
+	value V
+	cache *typedCache[K, V]
+}
+
+// ID implements the Cacheable interface.
+func (c *typedCacheable[K, V]) ID() string {
+	return c.id
+}
+
+// IsOutdated implements the Cacheable interface.
+func (c *typedCacheable[K, V]) IsOutdated() (bool, error) {
+	if o, ok := interface{}(c.value).(Outdater); ok {
+		return o.IsOutdated()
+	}
+	return false, nil
+}
+
+// Discard implements the Cacheable interface.
+func (c *typedCacheable[K, V]) Discard() error {
+	c.cache.release(c.id)
+	if d, ok := interface{}(c.value).(Discarder); ok {
+		return d.Discard()
+	}
+	return nil
+}
+
+// Weight implements the Weigher interface.
+func (c *typedCacheable[K, V]) Weight() int64 {
+	if w, ok := interface{}(c.value).(Weigher); ok {
+		return w.Weight()
+	}
+	return 1
+}
+
+// EOF
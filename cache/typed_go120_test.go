@@ -0,0 +1,52 @@
+// Tideland Go Library - Cache - Unit Tests
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+//go:build go1.20
+// +build go1.20
+
+package cache_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/cache"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// Interface types satisfy comparable only since Go 1.20.
+
+// TestTypedCacheInterfaceKeys tests that keys of different
+// types with the same representation don't collide.
+func TestTypedCacheInterfaceKeys(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	c, err := cache.NewTypedCache(func(key interface{}) (string, error) {
+		return fmt.Sprintf("%T", key), nil
+	}, cache.ID("typed-interface-keys"))
+	assert.Nil(err)
+	defer c.Stop()
+
+	value, err := c.Load("1", time.Second)
+	assert.Nil(err)
+	assert.Equal(value, "string")
+	value, err = c.Load(1, time.Second)
+	assert.Nil(err)
+	assert.Equal(value, "int")
+	assert.Equal(c.Len(), 2)
+}
+
+// EOF
@@ -0,0 +1,189 @@
+// Tideland Go Library - Cache - Unit Tests
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/cache"
+	"github.com/tideland/golib/errors"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestTypedCache tests the loading and discarding
+// of typed values.
+func TestTypedCache(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	discarded := []point{}
+	loader := func(p point) (*pointValue, error) {
+		if p.x < 0 {
+			return nil, errors.New(errLoading, errorMessages)
+		}
+		return &pointValue{p, &discarded}, nil
+	}
+
+	c, err := cache.NewTypedCache(loader, cache.ID("typed"), cache.MaxEntries(2))
+	assert.Nil(err)
+	defer c.Stop()
+
+	pv, err := c.Load(point{1, 2}, time.Second)
+	assert.Nil(err)
+	assert.Equal(pv.p, point{1, 2})
+	pv, err = c.Load(point{1, 2}, time.Second)
+	assert.Nil(err)
+	assert.Equal(pv.p, point{1, 2})
+	_, err = c.Load(point{-1, 2}, time.Second)
+	assert.ErrorMatch(err, ".*error during loading.*")
+	assert.Equal(c.Stats().Loads, int64(1))
+
+	// Evicted and discarded values are discarded.
+	_, err = c.Load(point{3, 4}, time.Second)
+	assert.Nil(err)
+	_, err = c.Load(point{5, 6}, time.Second)
+	assert.Nil(err)
+	assert.Equal(c.Len(), 2)
+	err = c.Discard(point{5, 6})
+	assert.Nil(err)
+	assert.Equal(c.Len(), 1)
+	assert.Equal(discarded, []point{{1, 2}, {5, 6}})
+}
+
+// TestTypedCacheStrings tests a typed cache with
+// string keys and values.
+func TestTypedCacheStrings(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	_, err := cache.NewTypedCache[string, string](nil)
+	assert.True(errors.IsError(err, cache.ErrNoLoader))
+
+	c, err := cache.NewTypedCache(func(key string) (string, error) {
+		return strings.ToUpper(key), nil
+	}, cache.ID("typed-strings"))
+	assert.Nil(err)
+	defer c.Stop()
+
+	for _, key := range []string{"a", "b", "a"} {
+		value, err := c.Load(key, time.Second)
+		assert.Nil(err)
+		assert.Equal(value, strings.ToUpper(key))
+	}
+	assert.Equal(c.Len(), 2)
+}
+
+// TestTypedCacheInterleaved tests loading values while
+// discarding them concurrently.
+func TestTypedCacheInterleaved(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	c, err := cache.NewTypedCache(func(key int) (int, error) {
+		return key * key, nil
+	}, cache.ID("typed-interleaved"), cache.NegativeTTL(time.Minute))
+	assert.Nil(err)
+	defer c.Stop()
+
+	var wg sync.WaitGroup
+	errc := make(chan error, 1)
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				value, err := c.Load(j%5, time.Second)
+				if err == nil && value != (j%5)*(j%5) {
+					err = errors.New(errLoading, errorMessages)
+				}
+				if err != nil && !errors.IsError(err, cache.ErrDiscardedWhileLoading) {
+					select {
+					case errc <- err:
+					default:
+					}
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				c.Discard(j % 5)
+			}
+		}()
+	}
+	wg.Wait()
+	select {
+	case err := <-errc:
+		assert.Nil(err)
+	default:
+	}
+	for key := 0; key < 5; key++ {
+		value, err := c.Load(key, time.Second)
+		assert.Nil(err)
+		assert.Equal(value, key*key)
+	}
+}
+
+// TestTypedCacheSnapshot tests loading restored Cacheables
+// not loaded by the typed cache.
+func TestTypedCacheSnapshot(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+	te := initEnvironment()
+	path := filepath.Join(td.String(), "typed.snapshot")
+	marshal, unmarshal := snapshotMarshalling(te)
+
+	c, err := cache.New(cache.ID("typed-snapshot"), cache.Loader(te.loader),
+		cache.Snapshot(path, marshal, unmarshal))
+	assert.Nil(err)
+	_, err = c.Load(idValidCacheable, time.Second)
+	assert.Nil(err)
+	err = c.Stop()
+	assert.Nil(err)
+
+	tc, err := cache.NewTypedCache(func(key string) (string, error) {
+		return key, nil
+	}, cache.ID("typed-snapshot"), cache.Snapshot(path, marshal, unmarshal))
+	assert.Nil(err)
+	defer tc.Stop()
+	_, err = tc.Load(idValidCacheable, time.Second)
+	assert.ErrorMatch(err, ".*has not been loaded by the typed cache.*")
+}
+
+//--------------------
+// HELPERS
+//--------------------
+
+// point is a key of the typed cache.
+type point struct {
+	x, y int
+}
+
+// pointValue is a value of the typed cache.
+type pointValue struct {
+	p         point
+	discarded *[]point
+}
+
+// Discard implements the cache.Discarder interface.
+func (pv *pointValue) Discard() error {
+	*pv.discarded = append(*pv.discarded, pv.p)
+	return nil
+}
+
+// EOF
@@ -0,0 +1,250 @@
+// Tideland Go Library - Cache - File Watcher
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tideland/golib/errors"
+	"github.com/tideland/golib/logger"
+	"github.com/tideland/golib/loop"
+)
+
+//--------------------
+// CONSTANTS
+//--------------------
+
+// defaultPoll is the polling interval if the native
+// notification is not available.
+const defaultPoll = time.Second
+
+//--------------------
+// NOTIFIER
+//--------------------
+
+// notifier signals the names of created, changed, or removed
+// files relative to the watched root directory.
+type notifier interface {
+	// Names returns the channel of the file names.
+	Names() <-chan string
+
+	// Close ends the notification.
+	Close() error
+}
+
+// pollingNotifier implements the notifier by periodically
+// comparing the names, modification times, and sizes of the files.
+type pollingNotifier struct {
+	root     string
+	interval time.Duration
+	files    map[string]os.FileInfo
+	namec    chan string
+	stopc    chan struct{}
+}
+
+// newPollingNotifier starts polling the root directory.
+func newPollingNotifier(root string, interval time.Duration) notifier {
+	n := &pollingNotifier{
+		root:     root,
+		interval: interval,
+		namec:    make(chan string),
+		stopc:    make(chan struct{}),
+	}
+	n.files = n.scan()
+	go n.run()
+	return n
+}
+
+// Names implements the notifier interface.
+func (n *pollingNotifier) Names() <-chan string {
+	return n.namec
+}
+
+// Close implements the notifier interface.
+func (n *pollingNotifier) Close() error {
+	close(n.stopc)
+	return nil
+}
+
+// run polls the root directory.
+func (n *pollingNotifier) run() {
+	defer close(n.namec)
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.stopc:
+			return
+		case <-ticker.C:
+			files := n.scan()
+			names := []string{}
+			for name, fi := range n.files {
+				nfi, ok := files[name]
+				if !ok || !nfi.ModTime().Equal(fi.ModTime()) || nfi.Size() != fi.Size() {
+					names = append(names, name)
+				}
+			}
+			// Created files may be negatively cached.
+			for name := range files {
+				if _, ok := n.files[name]; !ok {
+					names = append(names, name)
+				}
+			}
+			n.files = files
+			for _, name := range names {
+				select {
+				case <-n.stopc:
+					return
+				case n.namec <- name:
+				}
+			}
+		}
+	}
+}
+
+// scan reads the infos of all files below the root directory.
+func (n *pollingNotifier) scan() map[string]os.FileInfo {
+	files := make(map[string]os.FileInfo)
+	filepath.Walk(n.root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return nil
+		}
+		if name, err := filepath.Rel(n.root, path); err == nil {
+			files[name] = fi
+		}
+		return nil
+	})
+	return files
+}
+
+//--------------------
+// FILE WATCHER
+//--------------------
+
+// FileWatcher discards files of a cache using the file loader
+// as soon as they are created, changed, or removed.
+type FileWatcher interface {
+	// Stop ends the watching.
+	Stop() error
+}
+
+// fileWatcher implements the FileWatcher interface.
+type fileWatcher struct {
+	cache    Cache
+	root     string
+	notifier notifier
+	backend  loop.Loop
+}
+
+// WatchFiles starts watching the files below the root directory of
+// a cache using the file loader with the same root. Created, changed,
+// or removed files are discarded immediately, so the next Load returns
+// the new content, also if the file has been negatively cached before.
+// The files are discarded by their clean paths relative to the root,
+// e.g. "sub/file.txt", so only Cacheables loaded with such IDs are
+// matched, not those loaded as "./sub/file.txt" or "/sub/file.txt".
+// With a poll duration of 0 the native notification is used where
+// available, e.g. inotify on Linux. Otherwise, or if it fails, the
+// directory is polled every second. A poll duration larger than 0
+// forces polling in that interval, e.g. for network file systems.
+//
+//     c, err := cache.New(cache.Loader(cache.NewFileLoader(root, maxSize)))
+//     ...
+//     w, err := cache.WatchFiles(c, root, 0)
+//     ...
+//     defer w.Stop()
+func WatchFiles(c Cache, root string, poll time.Duration) (FileWatcher, error) {
+	fi, err := os.Stat(root)
+	if err != nil {
+		return nil, errors.Annotate(err, ErrFileWatching, errorMessages, root)
+	}
+	if !fi.IsDir() {
+		return nil, errors.New(ErrFileWatching, errorMessages, root)
+	}
+	var n notifier
+	if poll <= 0 {
+		n, err = newNativeNotifier(root)
+		if err != nil {
+			logger.Warningf("cannot natively watch %q, polling instead: %v", root, err)
+			poll = defaultPoll
+		}
+	}
+	if poll > 0 {
+		n = newPollingNotifier(root, poll)
+	}
+	w := &fileWatcher{
+		cache:    c,
+		root:     root,
+		notifier: n,
+	}
+	w.backend = loop.Go(w.backendLoop, "cache", "file-watcher", root)
+	return w, nil
+}
+
+// Stop implements the FileWatcher interface.
+func (w *fileWatcher) Stop() error {
+	return w.backend.Stop()
+}
+
+// backendLoop discards the notified files.
+func (w *fileWatcher) backendLoop(l loop.Loop) error {
+	for {
+		select {
+		case <-l.ShallStop():
+			return w.notifier.Close()
+		case name, ok := <-w.notifier.Names():
+			if !ok {
+				return errors.New(ErrFileWatching, errorMessages, w.root)
+			}
+			if err := w.cache.Discard(name); err != nil {
+				logger.Warningf("cannot discard changed file %q: %v", name, err)
+			}
+		}
+	}
+}
+
+//--------------------
+// PRELOADING
+//--------------------
+
+// PreloadFiles loads the files below the root directory matching
+// the pattern into a cache using the file loader with the same root.
+// The pattern is the one of filepath.Match, e.g. "templates/*.html".
+func PreloadFiles(c Cache, root, pattern string, timeout time.Duration) error {
+	paths, err := filepath.Glob(filepath.Join(root, pattern))
+	if err != nil {
+		return errors.Annotate(err, ErrFileLoading, errorMessages, pattern)
+	}
+	var errs []error
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil || fi.IsDir() {
+			continue
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			errs = append(errs, errors.Annotate(err, ErrFileLoading, errorMessages, path))
+			continue
+		}
+		if _, err = c.Load(name, timeout); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Collect(errs...)
+	}
+	return nil
+}
+
+// EOF
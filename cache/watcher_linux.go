@@ -0,0 +1,166 @@
+// Tideland Go Library - Cache - File Watcher - Linux
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+//go:build linux
+// +build linux
+
+package cache
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+//--------------------
+// CONSTANTS
+//--------------------
+
+// inotifyMask contains the watched events.
+const inotifyMask = syscall.IN_MODIFY | syscall.IN_ATTRIB | syscall.IN_CLOSE_WRITE |
+	syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+//--------------------
+// INOTIFY NOTIFIER
+//--------------------
+
+// inotifyNotifier implements the notifier using inotify.
+type inotifyNotifier struct {
+	mutex   sync.Mutex
+	root    string
+	fd      int
+	file    *os.File
+	watches map[int32]string
+	namec   chan string
+	stopc   chan struct{}
+}
+
+// newNativeNotifier starts watching the root directory
+// and its subdirectories with inotify.
+func newNativeNotifier(root string) (notifier, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_NONBLOCK | syscall.IN_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+	// Reading the file uses the runtime poller,
+	// so closing it ends the reading.
+	n := &inotifyNotifier{
+		root:    root,
+		fd:      fd,
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		watches: make(map[int32]string),
+		namec:   make(chan string),
+		stopc:   make(chan struct{}),
+	}
+	if err = n.addTree(root); err != nil {
+		n.file.Close()
+		return nil, err
+	}
+	go n.read()
+	return n, nil
+}
+
+// Names implements the notifier interface.
+func (n *inotifyNotifier) Names() <-chan string {
+	return n.namec
+}
+
+// Close implements the notifier interface.
+func (n *inotifyNotifier) Close() error {
+	close(n.stopc)
+	return n.file.Close()
+}
+
+// addTree adds watches for the directory and its subdirectories.
+func (n *inotifyNotifier) addTree(dir string) error {
+	return filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			return nil
+		}
+		wd, err := syscall.InotifyAddWatch(n.fd, path, inotifyMask)
+		if err != nil {
+			return err
+		}
+		n.mutex.Lock()
+		n.watches[int32(wd)] = path
+		n.mutex.Unlock()
+		return nil
+	})
+}
+
+// read reads the events until the file is closed.
+func (n *inotifyNotifier) read() {
+	defer close(n.namec)
+	var buffer [64 * (syscall.SizeofInotifyEvent + syscall.NAME_MAX + 1)]byte
+	for {
+		count, err := n.file.Read(buffer[:])
+		if err != nil {
+			return
+		}
+		offset := 0
+		for offset+syscall.SizeofInotifyEvent <= count {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buffer[offset]))
+			start := offset + syscall.SizeofInotifyEvent
+			offset = start + int(event.Len)
+			name := strings.TrimRight(string(buffer[start:offset]), "\x00")
+			n.handle(event.Wd, event.Mask, name)
+		}
+	}
+}
+
+// handle evaluates one event.
+func (n *inotifyNotifier) handle(wd int32, mask uint32, name string) {
+	n.mutex.Lock()
+	dir, ok := n.watches[wd]
+	if mask&syscall.IN_IGNORED != 0 {
+		delete(n.watches, wd)
+	}
+	n.mutex.Unlock()
+	if !ok || name == "" {
+		return
+	}
+	path := filepath.Join(dir, name)
+	if mask&syscall.IN_ISDIR != 0 {
+		if mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+			// Files may have been created before the
+			// directory has been watched.
+			n.addTree(path)
+			filepath.Walk(path, func(fpath string, fi os.FileInfo, err error) error {
+				if err == nil && !fi.IsDir() {
+					n.send(fpath)
+				}
+				return nil
+			})
+		}
+		return
+	}
+	// Created files are signalled too, they may
+	// be negatively cached.
+	n.send(path)
+}
+
+// send signals the path relative to the root directory.
+func (n *inotifyNotifier) send(path string) {
+	if rel, err := filepath.Rel(n.root, path); err == nil {
+		select {
+		case <-n.stopc:
+		case n.namec <- rel:
+		}
+	}
+}
+
+// EOF
@@ -0,0 +1,31 @@
+// Tideland Go Library - Cache - File Watcher - Other Platforms
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+//go:build !linux
+// +build !linux
+
+package cache
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"github.com/tideland/golib/errors"
+)
+
+//--------------------
+// NATIVE NOTIFIER
+//--------------------
+
+// newNativeNotifier is not supported on this platform,
+// so polling is used.
+func newNativeNotifier(root string) (notifier, error) {
+	return nil, errors.New(ErrFileWatching, errorMessages, root)
+}
+
+// EOF
@@ -0,0 +1,152 @@
+// Tideland Go Library - Cache - Unit Tests
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package cache_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/cache"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestWatchFilesNative tests the discarding of changed
+// and removed files using the native notification.
+func TestWatchFilesNative(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	testWatchFiles(assert, "watch-files-native", 0)
+}
+
+// TestWatchFilesPolling tests the discarding of changed
+// and removed files using polling.
+func TestWatchFilesPolling(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	testWatchFiles(assert, "watch-files-polling", 20*time.Millisecond)
+}
+
+// TestPreloadFiles tests the preloading of files
+// matching a pattern.
+func TestPreloadFiles(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+	td.Populate(audit.FileTree{
+		"a.html":     "a",
+		"b.html":     "b",
+		"c.txt":      "c",
+		"sub/d.html": "d",
+		"dir.html":   audit.FileTree{},
+	})
+
+	c, err := cache.New(cache.ID("preload-files"),
+		cache.Loader(cache.NewFileLoader(td.String(), 1024)))
+	assert.Nil(err)
+	defer c.Stop()
+
+	err = cache.PreloadFiles(c, td.String(), "*.html", time.Second)
+	assert.Nil(err)
+	assert.Equal(c.Len(), 2)
+	err = cache.PreloadFiles(c, td.String(), "sub/*.html", time.Second)
+	assert.Nil(err)
+	assert.Equal(c.Len(), 3)
+	err = cache.PreloadFiles(c, td.String(), "[", time.Second)
+	assert.ErrorMatch(err, ".*cannot load file '\\['.*")
+}
+
+//--------------------
+// HELPERS
+//--------------------
+
+// testWatchFiles runs the watching tests with the poll duration.
+func testWatchFiles(assert audit.Assertion, id string, poll time.Duration) {
+	td := audit.NewTempDir(assert)
+	defer td.Restore()
+	td.Populate(audit.FileTree{
+		"fa":     "one",
+		"sub/fb": "two",
+	})
+	root := td.String()
+
+	c, err := cache.New(cache.ID(id),
+		cache.Loader(cache.NewFileLoader(root, 1024)),
+		cache.NegativeTTL(time.Hour),
+		cache.NegativeMatcher(cache.IsFileNotExist))
+	assert.Nil(err)
+	defer c.Stop()
+	w, err := cache.WatchFiles(c, root, poll)
+	assert.Nil(err)
+	length := func(l int) func() bool {
+		return func() bool {
+			return c.Len() == l
+		}
+	}
+	load := func(name string) string {
+		cacheable, err := c.Load(name, time.Second)
+		assert.Nil(err)
+		rc, err := cacheable.(cache.FileCacheable).ReadCloser()
+		assert.Nil(err)
+		defer rc.Close()
+		data, err := ioutil.ReadAll(rc)
+		assert.Nil(err)
+		return string(data)
+	}
+
+	// Changed file.
+	assert.Equal(load("fa"), "one")
+	assert.Equal(load("sub/fb"), "two")
+	assert.Equal(c.Len(), 2)
+	err = ioutil.WriteFile(filepath.Join(root, "fa"), []byte("changed"), 0600)
+	assert.Nil(err)
+	assert.Retry(length(1), 100, 10*time.Millisecond)
+	assert.Equal(load("fa"), "changed")
+
+	// Removed file.
+	err = os.Remove(filepath.Join(root, "sub", "fb"))
+	assert.Nil(err)
+	assert.Retry(length(1), 100, 10*time.Millisecond)
+	_, err = c.Load("sub/fb", time.Second)
+	assert.True(cache.IsFileNotExist(err))
+
+	// Created file, negatively cached before.
+	_, err = c.Load("fc", time.Second)
+	assert.True(cache.IsFileNotExist(err))
+	assert.Equal(c.Len(), 3)
+	err = ioutil.WriteFile(filepath.Join(root, "fc"), []byte("three"), 0600)
+	assert.Nil(err)
+	assert.Retry(length(2), 100, 10*time.Millisecond)
+	assert.Equal(load("fc"), "three")
+
+	// File in a created directory.
+	_, err = c.Load("new/fd", time.Second)
+	assert.True(cache.IsFileNotExist(err))
+	assert.Equal(c.Len(), 4)
+	err = os.MkdirAll(filepath.Join(root, "new"), 0700)
+	assert.Nil(err)
+	err = ioutil.WriteFile(filepath.Join(root, "new", "fd"), []byte("four"), 0600)
+	assert.Nil(err)
+	assert.Retry(length(3), 100, 10*time.Millisecond)
+	assert.Equal(load("new/fd"), "four")
+
+	err = w.Stop()
+	assert.Nil(err)
+}
+
+// EOF
@@ -86,42 +86,42 @@ func (c *changer) Error() error {
 }
 
 //--------------------
-// STRING CHANGER
+// TYPED CHANGER
 //--------------------
 
-// stringChanger implements the StringChanger interface.
-type stringChanger struct {
+// typedChanger implements the TypedChanger interface. It's
+// also used as StringChanger.
+type typedChanger[T comparable] struct {
 	node *node
 	err  error
 }
 
-// Value implements the StringChanger interface.
-func (c *stringChanger) Value() (string, error) {
+// Value implements the TypedChanger interface.
+func (c *typedChanger[T]) Value() (T, error) {
 	if c.err != nil {
-		return "", c.err
-	}
-	if c.node.content.value() == nil {
-		return "", nil
+		var zero T
+		return zero, c.err
 	}
-	return c.node.content.value().(string), nil
+	return typedValue[T](c.node), nil
 }
 
-// SetValue implements the StringChanger interface.
-func (c *stringChanger) SetValue(v string) (string, error) {
+// SetValue implements the TypedChanger interface.
+func (c *typedChanger[T]) SetValue(v T) (T, error) {
+	var zero T
 	if c.err != nil {
-		return "", c.err
+		return zero, c.err
 	}
-	oldValue := c.node.content.value().(string)
+	oldValue := typedValue[T](c.node)
 	newValue := justValue{v}
 	if !c.node.isAllowed(newValue, true) {
-		return "", errors.New(ErrDuplicate, errorMessages)
+		return zero, errors.New(ErrDuplicate, errorMessages)
 	}
 	c.node.content = newValue
 	return oldValue, nil
 }
 
-// Add implements the StringChanger interface.
-func (c *stringChanger) Add(v string) error {
+// Add implements the TypedChanger interface.
+func (c *typedChanger[T]) Add(v T) error {
 	if c.err != nil {
 		return c.err
 	}
@@ -129,22 +129,22 @@ func (c *stringChanger) Add(v string) error {
 	return err
 }
 
-// Remove implements the StringChanger interface.
-func (c *stringChanger) Remove() error {
+// Remove implements the TypedChanger interface.
+func (c *typedChanger[T]) Remove() error {
 	if c.err != nil {
 		return c.err
 	}
 	return c.node.remove()
 }
 
-// List implements the StringChanger interface.
-func (c *stringChanger) List() ([]string, error) {
+// List implements the TypedChanger interface.
+func (c *typedChanger[T]) List() ([]T, error) {
 	if c.err != nil {
 		return nil, c.err
 	}
-	var list []string
+	var list []T
 	err := c.node.doChildren(func(cn *node) error {
-		list = append(list, cn.content.value().(string))
+		list = append(list, typedValue[T](cn))
 		return nil
 	})
 	if err != nil {
@@ -153,8 +153,8 @@ func (c *stringChanger) List() ([]string, error) {
 	return list, nil
 }
 
-// Error implements the StringChanger interface.
-func (c *stringChanger) Error() error {
+// Error implements the TypedChanger interface.
+func (c *typedChanger[T]) Error() error {
 	return c.err
 }
 
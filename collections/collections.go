@@ -35,8 +35,31 @@ type KeyStringValue struct {
 // COLLECTIONS - RING BUFFER
 //--------------------
 
+// TypedRingBuffer defines a buffer for values of type T which is
+// connected end-to-end. It grows if needed.
+type TypedRingBuffer[T any] interface {
+	fmt.Stringer
+
+	// Push adds values to the end of the buffer.
+	Push(values ...T)
+
+	// Peek returns the first value of the buffer. If the
+	// buffer is empty the second return value is false.
+	Peek() (T, bool)
+
+	// Pop removes and returns the first value of the buffer. If
+	// the buffer is empty the second return value is false.
+	Pop() (T, bool)
+
+	// Len returns the number of values in the buffer.
+	Len() int
+
+	// Cap returns the capacity of the buffer.
+	Cap() int
+}
+
 // RingBuffer defines a buffer which is connected end-to-end. It
-// grows if needed.
+// grows if needed. It's implemented by TypedRingBuffer[interface{}].
 type RingBuffer interface {
 	fmt.Stringer
 
@@ -62,7 +85,34 @@ type RingBuffer interface {
 // COLLECTIONS - STACKS
 //--------------------
 
-// Stack defines a stack containing any kind of values.
+// TypedStack defines a stack containing values of type T.
+type TypedStack[T any] interface {
+	fmt.Stringer
+
+	// Push adds values to the top of the stack.
+	Push(vs ...T)
+
+	// Pop removes and returns the top value of the stack.
+	Pop() (T, error)
+
+	// Peek returns the top value of the stack.
+	Peek() (T, error)
+
+	// All returns all values bottom-up.
+	All() []T
+
+	// AllReverse returns all values top-down.
+	AllReverse() []T
+
+	// Len returns the number of entries in the stack.
+	Len() int
+
+	// Deflate cleans the stack.
+	Deflate()
+}
+
+// Stack defines a stack containing any kind of values. It's
+// implemented by TypedStack[interface{}].
 type Stack interface {
 	fmt.Stringer
 
@@ -88,7 +138,8 @@ type Stack interface {
 	Deflate()
 }
 
-// StringStack defines a stack containing string values.
+// StringStack defines a stack containing string values. It's
+// implemented by TypedStack[string].
 type StringStack interface {
 	fmt.Stringer
 
@@ -114,10 +165,143 @@ type StringStack interface {
 	Deflate()
 }
 
+//--------------------
+// COLLECTIONS - PRIORITY QUEUES
+//--------------------
+
+// PriorityHandle references a value inside a priority queue.
+type PriorityHandle uint64
+
+// TypedPriorityQueue defines a queue returning values of type T
+// ordered by their priority. Values with equal priority are
+// returned in the order they have been pushed.
+type TypedPriorityQueue[T any] interface {
+	fmt.Stringer
+
+	// Push adds a value to the queue and returns its handle.
+	Push(value T) PriorityHandle
+
+	// Peek returns the value with the highest priority.
+	Peek() (T, error)
+
+	// Pop removes and returns the value with the highest priority.
+	Pop() (T, error)
+
+	// Update replaces the value of the handle, e.g. with a
+	// changed priority, and restores the order.
+	Update(h PriorityHandle, value T) error
+
+	// Fix restores the order after the priority of the value
+	// of the handle has been changed in place.
+	Fix(h PriorityHandle) error
+
+	// Remove removes and returns the value of the handle.
+	Remove(h PriorityHandle) (T, error)
+
+	// All returns all values ordered by priority.
+	All() []T
+
+	// Len returns the number of values in the queue.
+	Len() int
+
+	// Deflate cleans the queue.
+	Deflate()
+}
+
+// PriorityQueue defines a queue returning any kind of values
+// ordered by their priority. It's implemented by
+// TypedPriorityQueue[interface{}].
+type PriorityQueue interface {
+	fmt.Stringer
+
+	// Push adds a value to the queue and returns its handle.
+	Push(value interface{}) PriorityHandle
+
+	// Peek returns the value with the highest priority.
+	Peek() (interface{}, error)
+
+	// Pop removes and returns the value with the highest priority.
+	Pop() (interface{}, error)
+
+	// Update replaces the value of the handle, e.g. with a
+	// changed priority, and restores the order.
+	Update(h PriorityHandle, value interface{}) error
+
+	// Fix restores the order after the priority of the value
+	// of the handle has been changed in place.
+	Fix(h PriorityHandle) error
+
+	// Remove removes and returns the value of the handle.
+	Remove(h PriorityHandle) (interface{}, error)
+
+	// All returns all values ordered by priority.
+	All() []interface{}
+
+	// Len returns the number of values in the queue.
+	Len() int
+
+	// Deflate cleans the queue.
+	Deflate()
+}
+
 //--------------------
 // COLLECTIONS - SETS
 //--------------------
 
+// TypedSet defines a set containing values of type T.
+type TypedSet[T comparable] interface {
+	fmt.Stringer
+
+	// Add adds values to the set.
+	Add(vs ...T)
+
+	// Remove removes a value out if the set. It doesn't
+	// matter if the set does not contain the value.
+	Remove(vs ...T)
+
+	// Contains checks if the set contains a given value.
+	Contains(v T) bool
+
+	// All returns all values.
+	All() []T
+
+	// FindAll returns all values found by the
+	// passed function.
+	FindAll(f func(v T) (bool, error)) ([]T, error)
+
+	// DoAll executes the passed function on all values.
+	DoAll(f func(v T) error) error
+
+	// Len returns the number of entries in the set.
+	Len() int
+
+	// Deflate cleans the set.
+	Deflate()
+
+	// Union returns a new set containing the values of
+	// both sets.
+	Union(other TypedSet[T]) TypedSet[T]
+
+	// Intersection returns a new set containing the values
+	// contained in both sets.
+	Intersection(other TypedSet[T]) TypedSet[T]
+
+	// Difference returns a new set containing the values
+	// not contained in the other set.
+	Difference(other TypedSet[T]) TypedSet[T]
+
+	// SymmetricDifference returns a new set containing the
+	// values contained in only one of both sets.
+	SymmetricDifference(other TypedSet[T]) TypedSet[T]
+
+	// IsSubset checks if all values are contained in
+	// the other set.
+	IsSubset(other TypedSet[T]) bool
+
+	// Equal checks if both sets contain the same values.
+	Equal(other TypedSet[T]) bool
+}
+
 // Set defines a set containing any kind of values.
 type Set interface {
 	fmt.Stringer
@@ -147,9 +331,33 @@ type Set interface {
 
 	// Deflate cleans the stack.
 	Deflate()
+
+	// Union returns a new set containing the values of
+	// both sets.
+	Union(other Set) Set
+
+	// Intersection returns a new set containing the values
+	// contained in both sets.
+	Intersection(other Set) Set
+
+	// Difference returns a new set containing the values
+	// not contained in the other set.
+	Difference(other Set) Set
+
+	// SymmetricDifference returns a new set containing the
+	// values contained in only one of both sets.
+	SymmetricDifference(other Set) Set
+
+	// IsSubset checks if all values are contained in
+	// the other set.
+	IsSubset(other Set) bool
+
+	// Equal checks if both sets contain the same values.
+	Equal(other Set) bool
 }
 
-// StringSet defines a set containing string values.
+// StringSet defines a set containing string values. It's
+// based on TypedSet[string].
 type StringSet interface {
 	fmt.Stringer
 
@@ -163,7 +371,7 @@ type StringSet interface {
 	// Contains checks if a value is
 	Contains(v string) bool
 
-	// All returns all values.
+	// All returns all values sorted.
 	All() []string
 
 	// FindAll returns all values found by the
@@ -178,12 +386,59 @@ type StringSet interface {
 
 	// Deflate cleans the stack.
 	Deflate()
+
+	// Union returns a new set containing the values of
+	// both sets.
+	Union(other StringSet) StringSet
+
+	// Intersection returns a new set containing the values
+	// contained in both sets.
+	Intersection(other StringSet) StringSet
+
+	// Difference returns a new set containing the values
+	// not contained in the other set.
+	Difference(other StringSet) StringSet
+
+	// SymmetricDifference returns a new set containing the
+	// values contained in only one of both sets.
+	SymmetricDifference(other StringSet) StringSet
+
+	// IsSubset checks if all values are contained in
+	// the other set.
+	IsSubset(other StringSet) bool
+
+	// Equal checks if both sets contain the same values.
+	Equal(other StringSet) bool
 }
 
 //--------------------
 // COLLECTIONS - TREE CHANGERS
 //--------------------
 
+// TypedChanger defines the interface to perform changes on a tree
+// node with values of type T. It is returned by the addressing
+// operations like At() and Create() of the TypedTree.
+type TypedChanger[T comparable] interface {
+	// Value returns the changer node value.
+	Value() (T, error)
+
+	// SetValue sets the changer node value. It also returns
+	// the previous value.
+	SetValue(value T) (T, error)
+
+	// Add sets a child value.
+	Add(value T) error
+
+	// Remove deletes this changer node.
+	Remove() error
+
+	// List returns the values of the children of the changer node.
+	List() ([]T, error)
+
+	// Error returns a potential error of the changer.
+	Error() error
+}
+
 // Changer defines the interface to perform changes on a tree
 // node. It is returned by the addressing operations like At() and
 // Create() of the Tree.
@@ -210,7 +465,8 @@ type Changer interface {
 
 // StringChanger defines the interface to perform changes on a string
 // tree node. It is returned by the addressing operations like
-// At() and Create() of the StringTree.
+// At() and Create() of the StringTree. It's implemented by
+// TypedChanger[string].
 type StringChanger interface {
 	// Value returns the changer node value.
 	Value() (string, error)
@@ -301,6 +557,51 @@ type KeyStringValueChanger interface {
 // COLLECTIONS - TREES
 //--------------------
 
+// TypedTree defines the interface for a tree able to store values
+// of type T. They have to be comparable to navigate by them.
+type TypedTree[T comparable] interface {
+	fmt.Stringer
+
+	// At returns the changer of the path defined by the given
+	// values. If it does not exist it will not be created. Use
+	// Create() here. So to set a child at a given node path do
+	//
+	// err := tree.At(1, 2, 3).Add(4)
+	At(values ...T) TypedChanger[T]
+
+	// Root returns the top level changer.
+	Root() TypedChanger[T]
+
+	// Create returns the changer of the path defined by the
+	// given values. If it does not exist it will be created,
+	// but at least the root value has to be correct.
+	Create(values ...T) TypedChanger[T]
+
+	// FindFirst returns the changer for the first node found
+	// by the passed function.
+	FindFirst(f func(value T) (bool, error)) TypedChanger[T]
+
+	// FindAll returns all changers for the nodes found
+	// by the passed function.
+	FindAll(f func(value T) (bool, error)) []TypedChanger[T]
+
+	// DoAll executes the passed function on all nodes.
+	DoAll(f func(value T) error) error
+
+	// DoAllDeep executes the passed function on all nodes
+	// passing a deep list of values ordered top-down.
+	DoAllDeep(f func(values []T) error) error
+
+	// Len returns the number of nodes of the tree.
+	Len() int
+
+	// Copy creates a copy of the tree.
+	Copy() TypedTree[T]
+
+	// Deflate cleans the tree with a new root value.
+	Deflate(value T)
+}
+
 // Tree defines the interface for a tree able to store any type
 // of values.
 type Tree interface {
@@ -6,10 +6,14 @@
 // by the new BSD license.
 
 // Package collections of the Tideland Go Library provides some typical and
-// often used collection types like a ring buffer, stacks, sets and trees.
-// They are implemented as generic collections managing empty interfaces
-// as well as typed ones, e.g. for strings. They are not synchronized, so
-// this has to be done by the user.
+// often used collection types like a ring buffer, stacks, priority queues,
+// sets and trees.
+// They are implemented using generics, e.g. TypedStack[T] or TypedTree[T].
+// The ring buffer and the stack managing empty interfaces as well as the
+// collections for strings are based on them. Set and Tree keep their own
+// implementations, so they need no Go 1.20 for interface{} as comparable
+// type. The collections are not synchronized, so this has to be done by
+// the user.
 package collections
 
 // EOF
@@ -34,6 +34,7 @@ const (
 	ErrNodeDoChildren
 	ErrFindAll
 	ErrDoAll
+	ErrHandleNotFound
 )
 
 var errorMessages = errors.Messages{
@@ -50,6 +51,7 @@ var errorMessages = errors.Messages{
 	ErrNodeDoChildren:   "cannot perform function on child nodes",
 	ErrFindAll:          "cannot find all matching values",
 	ErrDoAll:            "cannot perform function on all values",
+	ErrHandleNotFound:   "priority queue handle not found",
 }
 
 //--------------------
@@ -0,0 +1,251 @@
+// Tideland Go Library - Collections - Priority Queue
+//
+// Copyright (C) 2015-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package collections
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+
+	"github.com/tideland/golib/errors"
+)
+
+//--------------------
+// ORDERED
+//--------------------
+
+// ordered contains the types supporting the operators
+// used by the min and max priority queues.
+type ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+//--------------------
+// PRIORITY HEAP
+//--------------------
+
+// priorityItem is one value inside the heap.
+type priorityItem[T any] struct {
+	value  T
+	handle PriorityHandle
+	index  int
+}
+
+// priorityHeap implements heap.Interface. It keeps the index
+// of each item up to date, so the items can be fixed and removed
+// by their handles.
+type priorityHeap[T any] struct {
+	items []*priorityItem[T]
+	less  func(a, b T) bool
+}
+
+// before checks if item a is returned before item b. Handles
+// are ascending, so they keep equal priorities stable.
+func (h *priorityHeap[T]) before(a, b *priorityItem[T]) bool {
+	if h.less(a.value, b.value) {
+		return true
+	}
+	if h.less(b.value, a.value) {
+		return false
+	}
+	return a.handle < b.handle
+}
+
+// Len implements heap.Interface.
+func (h *priorityHeap[T]) Len() int {
+	return len(h.items)
+}
+
+// Less implements heap.Interface.
+func (h *priorityHeap[T]) Less(i, j int) bool {
+	return h.before(h.items[i], h.items[j])
+}
+
+// Swap implements heap.Interface.
+func (h *priorityHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+// Push implements heap.Interface.
+func (h *priorityHeap[T]) Push(x interface{}) {
+	item := x.(*priorityItem[T])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+// Pop implements heap.Interface.
+func (h *priorityHeap[T]) Pop() interface{} {
+	last := len(h.items) - 1
+	item := h.items[last]
+	h.items[last] = nil
+	h.items = h.items[:last]
+	item.index = -1
+	return item
+}
+
+//--------------------
+// TYPED PRIORITY QUEUE
+//--------------------
+
+// typedPriorityQueue implements the TypedPriorityQueue interface.
+type typedPriorityQueue[T any] struct {
+	heap    *priorityHeap[T]
+	handles map[PriorityHandle]*priorityItem[T]
+	last    PriorityHandle
+}
+
+// NewTypedPriorityQueue creates a priority queue for values of type T.
+// The passed function returns true if a has a higher priority than b,
+// so a is returned first.
+//
+//     q := collections.NewTypedPriorityQueue(func(a, b *Job) bool {
+//         if a.Priority != b.Priority {
+//             return a.Priority > b.Priority
+//         }
+//         return a.Deadline.Before(b.Deadline)
+//     })
+func NewTypedPriorityQueue[T any](less func(a, b T) bool) TypedPriorityQueue[T] {
+	return &typedPriorityQueue[T]{
+		heap: &priorityHeap[T]{
+			less: less,
+		},
+		handles: make(map[PriorityHandle]*priorityItem[T]),
+	}
+}
+
+// NewMinPriorityQueue creates a priority queue returning
+// the smallest values first.
+func NewMinPriorityQueue[T ordered]() TypedPriorityQueue[T] {
+	return NewTypedPriorityQueue(func(a, b T) bool {
+		return a < b
+	})
+}
+
+// NewMaxPriorityQueue creates a priority queue returning
+// the largest values first.
+func NewMaxPriorityQueue[T ordered]() TypedPriorityQueue[T] {
+	return NewTypedPriorityQueue(func(a, b T) bool {
+		return a > b
+	})
+}
+
+// Push implements the TypedPriorityQueue interface.
+func (q *typedPriorityQueue[T]) Push(value T) PriorityHandle {
+	q.last++
+	item := &priorityItem[T]{
+		value:  value,
+		handle: q.last,
+	}
+	q.handles[item.handle] = item
+	heap.Push(q.heap, item)
+	return item.handle
+}
+
+// Peek implements the TypedPriorityQueue interface.
+func (q *typedPriorityQueue[T]) Peek() (T, error) {
+	if len(q.heap.items) == 0 {
+		var zero T
+		return zero, errors.New(ErrEmpty, errorMessages)
+	}
+	return q.heap.items[0].value, nil
+}
+
+// Pop implements the TypedPriorityQueue interface.
+func (q *typedPriorityQueue[T]) Pop() (T, error) {
+	if len(q.heap.items) == 0 {
+		var zero T
+		return zero, errors.New(ErrEmpty, errorMessages)
+	}
+	item := heap.Pop(q.heap).(*priorityItem[T])
+	delete(q.handles, item.handle)
+	return item.value, nil
+}
+
+// Update implements the TypedPriorityQueue interface.
+func (q *typedPriorityQueue[T]) Update(h PriorityHandle, value T) error {
+	item, ok := q.handles[h]
+	if !ok {
+		return errors.New(ErrHandleNotFound, errorMessages)
+	}
+	item.value = value
+	heap.Fix(q.heap, item.index)
+	return nil
+}
+
+// Fix implements the TypedPriorityQueue interface.
+func (q *typedPriorityQueue[T]) Fix(h PriorityHandle) error {
+	item, ok := q.handles[h]
+	if !ok {
+		return errors.New(ErrHandleNotFound, errorMessages)
+	}
+	heap.Fix(q.heap, item.index)
+	return nil
+}
+
+// Remove implements the TypedPriorityQueue interface.
+func (q *typedPriorityQueue[T]) Remove(h PriorityHandle) (T, error) {
+	item, ok := q.handles[h]
+	if !ok {
+		var zero T
+		return zero, errors.New(ErrHandleNotFound, errorMessages)
+	}
+	heap.Remove(q.heap, item.index)
+	delete(q.handles, h)
+	return item.value, nil
+}
+
+// All implements the TypedPriorityQueue interface.
+func (q *typedPriorityQueue[T]) All() []T {
+	items := make([]*priorityItem[T], len(q.heap.items))
+	copy(items, q.heap.items)
+	sort.Slice(items, func(i, j int) bool {
+		return q.heap.before(items[i], items[j])
+	})
+	all := make([]T, len(items))
+	for i, item := range items {
+		all[i] = item.value
+	}
+	return all
+}
+
+// Len implements the TypedPriorityQueue interface.
+func (q *typedPriorityQueue[T]) Len() int {
+	return len(q.heap.items)
+}
+
+// Deflate implements the TypedPriorityQueue interface.
+func (q *typedPriorityQueue[T]) Deflate() {
+	q.heap.items = nil
+	q.handles = make(map[PriorityHandle]*priorityItem[T])
+}
+
+// String implements the Stringer interface.
+func (q *typedPriorityQueue[T]) String() string {
+	return fmt.Sprintf("%v", q.All())
+}
+
+//--------------------
+// PRIORITY QUEUE
+//--------------------
+
+// NewPriorityQueue creates a priority queue for any kind of values.
+// The passed function returns true if a has a higher priority than b,
+// so a is returned first.
+func NewPriorityQueue(less func(a, b interface{}) bool) PriorityQueue {
+	return NewTypedPriorityQueue(less)
+}
+
+// EOF
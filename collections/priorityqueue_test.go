@@ -0,0 +1,160 @@
+// Tideland Go Library - Collections - Priority Queue - Unit Tests
+//
+// Copyright (C) 2015-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package collections_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"testing"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/collections"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestPriorityQueueMinMax tests the ordering of the min
+// and max priority queues.
+func TestPriorityQueueMinMax(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	minq := collections.NewMinPriorityQueue[int]()
+	maxq := collections.NewMaxPriorityQueue[int]()
+	for _, v := range []int{5, 3, 8, 1, 9, 3} {
+		minq.Push(v)
+		maxq.Push(v)
+	}
+	assert.Length(minq, 6)
+	assert.Equal(minq.String(), "[1 3 3 5 8 9]")
+	assert.Equal(maxq.String(), "[9 8 5 3 3 1]")
+
+	v, err := minq.Peek()
+	assert.Nil(err)
+	assert.Equal(v, 1)
+	popped := []int{}
+	for minq.Len() > 0 {
+		v, err = minq.Pop()
+		assert.Nil(err)
+		popped = append(popped, v)
+	}
+	assert.Equal(popped, []int{1, 3, 3, 5, 8, 9})
+
+	// Popping an empty queue returns an error.
+	_, err = minq.Pop()
+	assert.ErrorMatch(err, ".*collection is empty")
+	_, err = minq.Peek()
+	assert.ErrorMatch(err, ".*collection is empty")
+
+	maxq.Deflate()
+	assert.Length(maxq, 0)
+}
+
+// TestPriorityQueueStable tests that values with equal priorities
+// are returned in the order they have been pushed.
+func TestPriorityQueueStable(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	type job struct {
+		name     string
+		priority int
+	}
+	q := collections.NewTypedPriorityQueue(func(a, b job) bool {
+		return a.priority > b.priority
+	})
+	q.Push(job{"a", 1})
+	q.Push(job{"b", 2})
+	q.Push(job{"c", 1})
+	q.Push(job{"d", 2})
+	q.Push(job{"e", 1})
+
+	names := ""
+	for q.Len() > 0 {
+		j, err := q.Pop()
+		assert.Nil(err)
+		names += j.name
+	}
+	assert.Equal(names, "bdace")
+}
+
+// TestPriorityQueueHandles tests updating, fixing, and removing
+// values by their handles.
+func TestPriorityQueueHandles(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	type job struct {
+		name     string
+		priority int
+	}
+	q := collections.NewTypedPriorityQueue(func(a, b *job) bool {
+		return a.priority > b.priority
+	})
+	ha := q.Push(&job{"a", 1})
+	hb := q.Push(&job{"b", 2})
+	c := &job{"c", 3}
+	hc := q.Push(c)
+
+	// Update with a new value.
+	err := q.Update(ha, &job{"a", 4})
+	assert.Nil(err)
+	j, err := q.Peek()
+	assert.Nil(err)
+	assert.Equal(j.name, "a")
+
+	// Change a value in place and fix it.
+	c.priority = 5
+	err = q.Fix(hc)
+	assert.Nil(err)
+	j, err = q.Peek()
+	assert.Nil(err)
+	assert.Equal(j.name, "c")
+
+	// Remove by handle.
+	j, err = q.Remove(hc)
+	assert.Nil(err)
+	assert.Equal(j.name, "c")
+	assert.Length(q, 2)
+	_, err = q.Remove(hc)
+	assert.ErrorMatch(err, ".* priority queue handle not found")
+	err = q.Update(hc, &job{"c", 3})
+	assert.ErrorMatch(err, ".* priority queue handle not found")
+
+	// Popped values have no handle anymore.
+	j, err = q.Pop()
+	assert.Nil(err)
+	assert.Equal(j.name, "a")
+	err = q.Fix(ha)
+	assert.ErrorMatch(err, ".* priority queue handle not found")
+	j, err = q.Pop()
+	assert.Nil(err)
+	assert.Equal(j.name, "b")
+	err = q.Fix(hb)
+	assert.ErrorMatch(err, ".* priority queue handle not found")
+}
+
+// TestPriorityQueue tests the priority queue for any kind of values.
+func TestPriorityQueue(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	q := collections.NewPriorityQueue(func(a, b interface{}) bool {
+		return len(a.(string)) < len(b.(string))
+	})
+	q.Push("charlie")
+	q.Push("bravo")
+	q.Push("alpha")
+	q.Push("tango")
+	q.Push("x")
+	assert.Equal(q.All(), []interface{}{"x", "bravo", "alpha", "tango", "charlie"})
+	assert.Equal(q.String(), "[x bravo alpha tango charlie]")
+	assert.Length(q, 5)
+}
+
+// EOF
@@ -17,34 +17,33 @@ import (
 )
 
 //--------------------
-// RING BUFFER
+// TYPED RING BUFFER
 //--------------------
 
 // valueLink is one ring buffer element containing one
 // value and linking to the next element.
-type valueLink struct {
+type valueLink[T any] struct {
 	used  bool
-	value interface{}
-	next  *valueLink
+	value T
+	next  *valueLink[T]
 }
 
-// ringBuffer implements the RingBuffer interface.
-type ringBuffer struct {
-	start   *valueLink
-	end     *valueLink
-	current *valueLink
+// typedRingBuffer implements the TypedRingBuffer interface.
+type typedRingBuffer[T any] struct {
+	start *valueLink[T]
+	end   *valueLink[T]
 }
 
-// NewRingBuffer creates a new ring buffer.
-func NewRingBuffer(size int) RingBuffer {
-	rb := &ringBuffer{}
-	rb.start = &valueLink{}
+// NewTypedRingBuffer creates a new ring buffer for values of type T.
+func NewTypedRingBuffer[T any](size int) TypedRingBuffer[T] {
+	rb := &typedRingBuffer[T]{}
+	rb.start = &valueLink[T]{}
 	rb.end = rb.start
 	if size < 2 {
 		size = 2
 	}
 	for i := 0; i < size-1; i++ {
-		link := &valueLink{}
+		link := &valueLink[T]{}
 		rb.end.next = link
 		rb.end = link
 	}
@@ -52,8 +51,8 @@ func NewRingBuffer(size int) RingBuffer {
 	return rb
 }
 
-// Push implements the RingBuffer interface.
-func (rb *ringBuffer) Push(values ...interface{}) {
+// Push implements the TypedRingBuffer interface.
+func (rb *typedRingBuffer[T]) Push(values ...T) {
 	for _, value := range values {
 		if rb.end.next.used == false {
 			rb.end.next.used = true
@@ -61,7 +60,7 @@ func (rb *ringBuffer) Push(values ...interface{}) {
 			rb.end = rb.end.next
 			continue
 		}
-		link := &valueLink{
+		link := &valueLink[T]{
 			used:  true,
 			value: value,
 			next:  rb.start,
@@ -71,28 +70,30 @@ func (rb *ringBuffer) Push(values ...interface{}) {
 	}
 }
 
-// Peek implements the RingBuffer interface.
-func (rb *ringBuffer) Peek() (interface{}, bool) {
+// Peek implements the TypedRingBuffer interface.
+func (rb *typedRingBuffer[T]) Peek() (T, bool) {
 	if rb.start.used == false {
-		return nil, false
+		var zero T
+		return zero, false
 	}
 	return rb.start.value, true
 }
 
-// Pop implements the RingBuffer interface.
-func (rb *ringBuffer) Pop() (interface{}, bool) {
+// Pop implements the TypedRingBuffer interface.
+func (rb *typedRingBuffer[T]) Pop() (T, bool) {
+	var zero T
 	if rb.start.used == false {
-		return nil, false
+		return zero, false
 	}
 	value := rb.start.value
 	rb.start.used = false
-	rb.start.value = nil
+	rb.start.value = zero
 	rb.start = rb.start.next
 	return value, true
 }
 
-// Len implements the RingBuffer interface.
-func (rb *ringBuffer) Len() int {
+// Len implements the TypedRingBuffer interface.
+func (rb *typedRingBuffer[T]) Len() int {
 	l := 0
 	current := rb.start
 	for current.used {
@@ -105,8 +106,8 @@ func (rb *ringBuffer) Len() int {
 	return l
 }
 
-// Cap implements the RingBuffer interface.
-func (rb *ringBuffer) Cap() int {
+// Cap implements the TypedRingBuffer interface.
+func (rb *typedRingBuffer[T]) Cap() int {
 	c := 1
 	current := rb.start
 	for current.next != rb.start {
@@ -117,7 +118,7 @@ func (rb *ringBuffer) Cap() int {
 }
 
 // String implements the Stringer interface.
-func (rb *ringBuffer) String() string {
+func (rb *typedRingBuffer[T]) String() string {
 	vs := []string{}
 	current := rb.start
 	for current.used {
@@ -130,4 +131,13 @@ func (rb *ringBuffer) String() string {
 	return strings.Join(vs, "->")
 }
 
+//--------------------
+// RING BUFFER
+//--------------------
+
+// NewRingBuffer creates a new ring buffer.
+func NewRingBuffer(size int) RingBuffer {
+	return NewTypedRingBuffer[interface{}](size)
+}
+
 // EOF
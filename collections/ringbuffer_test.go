@@ -101,4 +101,26 @@ func TestRingBufferGrow(t *testing.T) {
 	assert.Length(rb, 6)
 }
 
+// TestTypedRingBuffer tests the typed ring buffer.
+func TestTypedRingBuffer(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	rb := collections.NewTypedRingBuffer[string](2)
+	v, ok := rb.Pop()
+	assert.False(ok)
+	assert.Equal(v, "")
+
+	rb.Push("alpha", "bravo", "charlie")
+	assert.Length(rb, 3)
+	assert.Equal(rb.Cap(), 3)
+	assert.Equal(rb.String(), "[alpha]->[bravo]->[charlie]")
+	v, ok = rb.Peek()
+	assert.True(ok)
+	assert.Equal(v, "alpha")
+	v, ok = rb.Pop()
+	assert.True(ok)
+	assert.Equal(v, "alpha")
+	assert.Length(rb, 2)
+}
+
 // EOF
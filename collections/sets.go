@@ -13,59 +13,60 @@ package collections
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/tideland/golib/errors"
 )
 
 //--------------------
-// SET
+// TYPED SET
 //--------------------
 
-// set implements the Set interface.
-type set struct {
-	values map[interface{}]struct{}
+// typedSet implements the TypedSet interface.
+type typedSet[T comparable] struct {
+	values map[T]struct{}
 }
 
-// NewSet creates a set with the passed values
-// as initial content.
-func NewSet(vs ...interface{}) Set {
-	s := &set{make(map[interface{}]struct{})}
+// NewTypedSet creates a set of values of type T with
+// the passed values as initial content.
+func NewTypedSet[T comparable](vs ...T) TypedSet[T] {
+	s := &typedSet[T]{make(map[T]struct{})}
 	s.Add(vs...)
 	return s
 }
 
-// Add implements the Set interface.
-func (s *set) Add(vs ...interface{}) {
+// Add implements the TypedSet interface.
+func (s *typedSet[T]) Add(vs ...T) {
 	for _, v := range vs {
 		s.values[v] = struct{}{}
 	}
 }
 
-// Remove implements the Set interface.
-func (s *set) Remove(vs ...interface{}) {
+// Remove implements the TypedSet interface.
+func (s *typedSet[T]) Remove(vs ...T) {
 	for _, v := range vs {
 		delete(s.values, v)
 	}
 }
 
-// Contains implements the Set interface.
-func (s *set) Contains(v interface{}) bool {
+// Contains implements the TypedSet interface.
+func (s *typedSet[T]) Contains(v T) bool {
 	_, ok := s.values[v]
 	return ok
 }
 
-// All implements the Set interface.
-func (s *set) All() []interface{} {
-	all := []interface{}{}
+// All implements the TypedSet interface.
+func (s *typedSet[T]) All() []T {
+	all := []T{}
 	for v := range s.values {
 		all = append(all, v)
 	}
 	return all
 }
 
-// FindAll implements the Set interface.
-func (s *set) FindAll(f func(v interface{}) (bool, error)) ([]interface{}, error) {
-	found := []interface{}{}
+// FindAll implements the TypedSet interface.
+func (s *typedSet[T]) FindAll(f func(v T) (bool, error)) ([]T, error) {
+	found := []T{}
 	for v := range s.values {
 		ok, err := f(v)
 		if err != nil {
@@ -78,8 +79,8 @@ func (s *set) FindAll(f func(v interface{}) (bool, error)) ([]interface{}, error
 	return found, nil
 }
 
-// DoAll implements the Set interface.
-func (s *set) DoAll(f func(v interface{}) error) error {
+// DoAll implements the TypedSet interface.
+func (s *typedSet[T]) DoAll(f func(v T) error) error {
 	for v := range s.values {
 		if err := f(v); err != nil {
 			return errors.Annotate(err, ErrDoAll, errorMessages)
@@ -88,71 +89,155 @@ func (s *set) DoAll(f func(v interface{}) error) error {
 	return nil
 }
 
-// Len implements the Set interface.
-func (s *set) Len() int {
+// Len implements the TypedSet interface.
+func (s *typedSet[T]) Len() int {
 	return len(s.values)
 }
 
-// Deflate implements the Set interface.
-func (s *set) Deflate() {
-	s.values = make(map[interface{}]struct{})
+// Deflate implements the TypedSet interface.
+func (s *typedSet[T]) Deflate() {
+	s.values = make(map[T]struct{})
 }
 
-// Deflate implements the Stringer interface.
-func (s *set) String() string {
+// String implements the Stringer interface.
+func (s *typedSet[T]) String() string {
 	all := s.All()
 	return fmt.Sprintf("%v", all)
 }
 
+// Union implements the TypedSet interface.
+func (s *typedSet[T]) Union(other TypedSet[T]) TypedSet[T] {
+	return union[T](s, other)
+}
+
+// Intersection implements the TypedSet interface.
+func (s *typedSet[T]) Intersection(other TypedSet[T]) TypedSet[T] {
+	return intersection[T](s, other)
+}
+
+// Difference implements the TypedSet interface.
+func (s *typedSet[T]) Difference(other TypedSet[T]) TypedSet[T] {
+	return difference[T](s, other)
+}
+
+// SymmetricDifference implements the TypedSet interface.
+func (s *typedSet[T]) SymmetricDifference(other TypedSet[T]) TypedSet[T] {
+	return union[T](difference[T](s, other), difference[T](other, s))
+}
+
+// IsSubset implements the TypedSet interface.
+func (s *typedSet[T]) IsSubset(other TypedSet[T]) bool {
+	return isSubset[T](s, other)
+}
+
+// Equal implements the TypedSet interface.
+func (s *typedSet[T]) Equal(other TypedSet[T]) bool {
+	return s.Len() == other.Len() && isSubset[T](s, other)
+}
+
 //--------------------
-// STRING SET
+// SET ALGEBRA
 //--------------------
 
-// stringSet implements the StringSet interface.
-type stringSet struct {
-	values map[string]struct{}
+// algebraSet contains the methods needed for the set algebra, so
+// it works for the typed sets as well as for StringSet. Set has its
+// own implementation, interface{} satisfies comparable only since
+// Go 1.20.
+type algebraSet[T comparable] interface {
+	Contains(v T) bool
+	All() []T
 }
 
-// NewStringSet creates a string set with the passed values
+// union returns a new set containing the values of a and b.
+func union[T comparable](a, b algebraSet[T]) TypedSet[T] {
+	u := NewTypedSet(a.All()...)
+	u.Add(b.All()...)
+	return u
+}
+
+// intersection returns a new set containing the values of a
+// also contained in b.
+func intersection[T comparable](a, b algebraSet[T]) TypedSet[T] {
+	i := NewTypedSet[T]()
+	for _, v := range a.All() {
+		if b.Contains(v) {
+			i.Add(v)
+		}
+	}
+	return i
+}
+
+// difference returns a new set containing the values of a
+// not contained in b.
+func difference[T comparable](a, b algebraSet[T]) TypedSet[T] {
+	d := NewTypedSet[T]()
+	for _, v := range a.All() {
+		if !b.Contains(v) {
+			d.Add(v)
+		}
+	}
+	return d
+}
+
+// isSubset checks if all values of a are contained in b.
+func isSubset[T comparable](a, b algebraSet[T]) bool {
+	for _, v := range a.All() {
+		if !b.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+//--------------------
+// SET
+//--------------------
+
+// set implements the Set interface.
+type set struct {
+	values map[interface{}]struct{}
+}
+
+// NewSet creates a set with the passed values
 // as initial content.
-func NewStringSet(vs ...string) StringSet {
-	s := &stringSet{make(map[string]struct{})}
+func NewSet(vs ...interface{}) Set {
+	s := &set{make(map[interface{}]struct{})}
 	s.Add(vs...)
 	return s
 }
 
-// Add implements the StringSet interface.
-func (s *stringSet) Add(vs ...string) {
+// Add implements the Set interface.
+func (s *set) Add(vs ...interface{}) {
 	for _, v := range vs {
 		s.values[v] = struct{}{}
 	}
 }
 
-// Remove implements the StringSet interface.
-func (s *stringSet) Remove(vs ...string) {
+// Remove implements the Set interface.
+func (s *set) Remove(vs ...interface{}) {
 	for _, v := range vs {
 		delete(s.values, v)
 	}
 }
 
-// Contains implements the StringSet interface.
-func (s *stringSet) Contains(v string) bool {
+// Contains implements the Set interface.
+func (s *set) Contains(v interface{}) bool {
 	_, ok := s.values[v]
 	return ok
 }
 
-// All implements the StringSet interface.
-func (s *stringSet) All() []string {
-	all := []string{}
+// All implements the Set interface.
+func (s *set) All() []interface{} {
+	all := []interface{}{}
 	for v := range s.values {
 		all = append(all, v)
 	}
 	return all
 }
 
-// FindAll implements the StringSet interface.
-func (s *stringSet) FindAll(f func(v string) (bool, error)) ([]string, error) {
-	found := []string{}
+// FindAll implements the Set interface.
+func (s *set) FindAll(f func(v interface{}) (bool, error)) ([]interface{}, error) {
+	found := []interface{}{}
 	for v := range s.values {
 		ok, err := f(v)
 		if err != nil {
@@ -165,8 +250,8 @@ func (s *stringSet) FindAll(f func(v string) (bool, error)) ([]string, error) {
 	return found, nil
 }
 
-// DoAll implements the StringSet interface.
-func (s *stringSet) DoAll(f func(v string) error) error {
+// DoAll implements the Set interface.
+func (s *set) DoAll(f func(v interface{}) error) error {
 	for v := range s.values {
 		if err := f(v); err != nil {
 			return errors.Annotate(err, ErrDoAll, errorMessages)
@@ -175,20 +260,127 @@ func (s *stringSet) DoAll(f func(v string) error) error {
 	return nil
 }
 
-// Len implements the StringSet interface.
-func (s *stringSet) Len() int {
+// Len implements the Set interface.
+func (s *set) Len() int {
 	return len(s.values)
 }
 
-// Deflate implements the StringSet interface.
-func (s *stringSet) Deflate() {
-	s.values = make(map[string]struct{})
+// Deflate implements the Set interface.
+func (s *set) Deflate() {
+	s.values = make(map[interface{}]struct{})
 }
 
 // Deflate implements the Stringer interface.
-func (s *stringSet) String() string {
+func (s *set) String() string {
 	all := s.All()
 	return fmt.Sprintf("%v", all)
 }
 
+// Union implements the Set interface.
+func (s *set) Union(other Set) Set {
+	u := NewSet(s.All()...)
+	u.Add(other.All()...)
+	return u
+}
+
+// Intersection implements the Set interface.
+func (s *set) Intersection(other Set) Set {
+	i := NewSet()
+	for _, v := range s.All() {
+		if other.Contains(v) {
+			i.Add(v)
+		}
+	}
+	return i
+}
+
+// Difference implements the Set interface.
+func (s *set) Difference(other Set) Set {
+	d := NewSet()
+	for _, v := range s.All() {
+		if !other.Contains(v) {
+			d.Add(v)
+		}
+	}
+	return d
+}
+
+// SymmetricDifference implements the Set interface.
+func (s *set) SymmetricDifference(other Set) Set {
+	return s.Difference(other).Union(other.Difference(s))
+}
+
+// IsSubset implements the Set interface.
+func (s *set) IsSubset(other Set) bool {
+	for _, v := range s.All() {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal implements the Set interface.
+func (s *set) Equal(other Set) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+//--------------------
+// STRING SET
+//--------------------
+
+// stringSet implements the StringSet interface based
+// on a TypedSet[string].
+type stringSet struct {
+	TypedSet[string]
+}
+
+// NewStringSet creates a string set with the passed values
+// as initial content.
+func NewStringSet(vs ...string) StringSet {
+	return &stringSet{NewTypedSet(vs...)}
+}
+
+// All implements the StringSet interface.
+func (s *stringSet) All() []string {
+	all := s.TypedSet.All()
+	sort.Strings(all)
+	return all
+}
+
+// Union implements the StringSet interface.
+func (s *stringSet) Union(other StringSet) StringSet {
+	return &stringSet{union[string](s, other)}
+}
+
+// Intersection implements the StringSet interface.
+func (s *stringSet) Intersection(other StringSet) StringSet {
+	return &stringSet{intersection[string](s, other)}
+}
+
+// Difference implements the StringSet interface.
+func (s *stringSet) Difference(other StringSet) StringSet {
+	return &stringSet{difference[string](s, other)}
+}
+
+// SymmetricDifference implements the StringSet interface.
+func (s *stringSet) SymmetricDifference(other StringSet) StringSet {
+	return s.Difference(other).Union(other.Difference(s))
+}
+
+// IsSubset implements the StringSet interface.
+func (s *stringSet) IsSubset(other StringSet) bool {
+	return isSubset[string](s, other)
+}
+
+// Equal implements the StringSet interface.
+func (s *stringSet) Equal(other StringSet) bool {
+	return s.Len() == other.Len() && isSubset[string](s, other)
+}
+
+// String implements the Stringer interface.
+func (s *stringSet) String() string {
+	return fmt.Sprintf("%v", s.All())
+}
+
 // EOF
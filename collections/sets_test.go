@@ -144,4 +144,96 @@ func TestStringSetsDoAll(t *testing.T) {
 	assert.ErrorMatch(err, ".* cannot perform function on all values: ouch")
 }
 
+// TestTypedSetsAddRemove tests the core typed set methods.
+func TestTypedSetsAddRemove(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	set := collections.NewTypedSet(1, 2, 3, 2, 1)
+	assert.Length(set, 3)
+	set.Add(4, 5)
+	assert.Length(set, 5)
+	assert.True(set.Contains(4))
+	set.Remove(1, 4)
+	assert.Length(set, 3)
+	assert.False(set.Contains(4))
+
+	odd, err := set.FindAll(func(v int) (bool, error) {
+		return v%2 == 1, nil
+	})
+	assert.Nil(err)
+	assert.Length(odd, 2)
+
+	sum := 0
+	err = set.DoAll(func(v int) error {
+		sum += v
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(sum, 10)
+	err = set.DoAll(func(v int) error {
+		return errors.New("ouch")
+	})
+	assert.ErrorMatch(err, ".* cannot perform function on all values: ouch")
+
+	set.Deflate()
+	assert.Length(set, 0)
+}
+
+// TestSetsAlgebra tests the set algebra.
+func TestSetsAlgebra(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	a := collections.NewSet(1, 2, 3, "foo")
+	b := collections.NewSet(3, "foo", true)
+
+	assert.Length(a.Union(b), 5)
+	assert.True(a.Intersection(b).Equal(collections.NewSet(3, "foo")))
+	assert.True(a.Difference(b).Equal(collections.NewSet(1, 2)))
+	assert.True(a.SymmetricDifference(b).Equal(collections.NewSet(1, 2, true)))
+	assert.True(a.Intersection(b).IsSubset(a))
+	assert.False(a.IsSubset(b))
+	assert.False(a.Equal(b))
+
+	// The sets themselves are unchanged.
+	assert.Length(a, 4)
+	assert.Length(b, 3)
+}
+
+// TestStringSetsAlgebra tests the string set algebra and
+// the sorting of the values.
+func TestStringSetsAlgebra(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	a := collections.NewStringSet("delta", "alpha", "charlie", "bravo")
+	b := collections.NewStringSet("echo", "charlie", "alpha")
+	assert.Equal(a.All(), []string{"alpha", "bravo", "charlie", "delta"})
+	assert.Equal(a.String(), "[alpha bravo charlie delta]")
+
+	assert.Equal(a.Union(b).All(), []string{"alpha", "bravo", "charlie", "delta", "echo"})
+	assert.Equal(a.Intersection(b).All(), []string{"alpha", "charlie"})
+	assert.Equal(a.Difference(b).All(), []string{"bravo", "delta"})
+	assert.Equal(b.Difference(a).All(), []string{"echo"})
+	assert.Equal(a.SymmetricDifference(b).All(), []string{"bravo", "delta", "echo"})
+	assert.True(collections.NewStringSet("bravo", "alpha").IsSubset(a))
+	assert.False(b.IsSubset(a))
+	assert.True(a.Equal(collections.NewStringSet("alpha", "bravo", "charlie", "delta")))
+	assert.False(a.Equal(b))
+	assert.Length(collections.NewStringSet().Intersection(a), 0)
+}
+
+// TestTypedSetsAlgebra tests the typed set algebra.
+func TestTypedSetsAlgebra(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	a := collections.NewTypedSet(1, 2, 3, 4)
+	b := collections.NewTypedSet(3, 4, 5)
+
+	assert.True(a.Union(b).Equal(collections.NewTypedSet(1, 2, 3, 4, 5)))
+	assert.True(a.Intersection(b).Equal(collections.NewTypedSet(3, 4)))
+	assert.True(a.Difference(b).Equal(collections.NewTypedSet(1, 2)))
+	assert.True(a.SymmetricDifference(b).Equal(collections.NewTypedSet(1, 2, 5)))
+	assert.True(collections.NewTypedSet[int]().IsSubset(b))
+	assert.False(b.IsSubset(a))
+}
+
 // EOF
@@ -18,155 +18,101 @@ import (
 )
 
 //--------------------
-// STACK
+// TYPED STACK
 //--------------------
 
-// stack implements the Stack interface.
-type stack struct {
-	values []interface{}
+// typedStack implements the TypedStack interface.
+type typedStack[T any] struct {
+	values []T
 }
 
-// NewStack creates a stack with the passed values
-// as initial content.
-func NewStack(vs ...interface{}) Stack {
-	return &stack{
+// NewTypedStack creates a stack of values of type T with
+// the passed values as initial content.
+func NewTypedStack[T any](vs ...T) TypedStack[T] {
+	return &typedStack[T]{
 		values: vs,
 	}
 }
 
-// Push implements the Stack interface.
-func (s *stack) Push(vs ...interface{}) {
+// Push implements the TypedStack interface.
+func (s *typedStack[T]) Push(vs ...T) {
 	s.values = append(s.values, vs...)
 }
 
-// Pop implements the Stack interface.
-func (s *stack) Pop() (interface{}, error) {
+// Pop implements the TypedStack interface.
+func (s *typedStack[T]) Pop() (T, error) {
 	lv := len(s.values)
 	if lv == 0 {
-		return nil, errors.New(ErrEmpty, errorMessages)
+		var zero T
+		return zero, errors.New(ErrEmpty, errorMessages)
 	}
 	v := s.values[lv-1]
 	s.values = s.values[:lv-1]
 	return v, nil
 }
 
-// Peek implements the Stack interface.
-func (s stack) Peek() (interface{}, error) {
+// Peek implements the TypedStack interface.
+func (s *typedStack[T]) Peek() (T, error) {
 	lv := len(s.values)
 	if lv == 0 {
-		return nil, errors.New(ErrEmpty, errorMessages)
+		var zero T
+		return zero, errors.New(ErrEmpty, errorMessages)
 	}
 	v := s.values[lv-1]
 	return v, nil
 }
 
-// All implements the Stack interface.
-func (s *stack) All() []interface{} {
+// All implements the TypedStack interface.
+func (s *typedStack[T]) All() []T {
 	sl := len(s.values)
-	all := make([]interface{}, sl)
+	all := make([]T, sl)
 	copy(all, s.values)
 	return all
 }
 
-// AllReverse implements the Stack interface.
-func (s *stack) AllReverse() []interface{} {
+// AllReverse implements the TypedStack interface.
+func (s *typedStack[T]) AllReverse() []T {
 	sl := len(s.values)
-	all := make([]interface{}, sl)
+	all := make([]T, sl)
 	for i, value := range s.values {
 		all[sl-1-i] = value
 	}
 	return all
 }
 
-// Len implements the Stack interface.
-func (s *stack) Len() int {
+// Len implements the TypedStack interface.
+func (s *typedStack[T]) Len() int {
 	return len(s.values)
 }
 
-// Deflate implements the Stack interface.
-func (s *stack) Deflate() {
-	s.values = []interface{}{}
+// Deflate implements the TypedStack interface.
+func (s *typedStack[T]) Deflate() {
+	s.values = []T{}
 }
 
-// Deflate implements the Stringer interface.
-func (s *stack) String() string {
+// String implements the Stringer interface.
+func (s *typedStack[T]) String() string {
 	return fmt.Sprintf("%v", s.values)
 }
 
 //--------------------
-// STRING STACK
+// STACK
 //--------------------
 
-// stringStack implements the StringStack interface.
-type stringStack struct {
-	values []string
+// NewStack creates a stack with the passed values
+// as initial content.
+func NewStack(vs ...interface{}) Stack {
+	return NewTypedStack(vs...)
 }
 
+//--------------------
+// STRING STACK
+//--------------------
+
 // NewStringStack creates a string stack with the passed values
 // as initial content.
 func NewStringStack(vs ...string) StringStack {
-	return &stringStack{
-		values: vs,
-	}
-}
-
-// Push implements the StringStack interface.
-func (s *stringStack) Push(vs ...string) {
-	s.values = append(s.values, vs...)
-}
-
-// Pop implements the StringStack interface.
-func (s *stringStack) Pop() (string, error) {
-	lv := len(s.values)
-	if lv == 0 {
-		return "", errors.New(ErrEmpty, errorMessages)
-	}
-	v := s.values[lv-1]
-	s.values = s.values[:lv-1]
-	return v, nil
-}
-
-// Peek implements the StringStack interface.
-func (s *stringStack) Peek() (string, error) {
-	lv := len(s.values)
-	if lv == 0 {
-		return "", errors.New(ErrEmpty, errorMessages)
-	}
-	v := s.values[lv-1]
-	return v, nil
-}
-
-// All implements the StringStack interface.
-func (s *stringStack) All() []string {
-	sl := len(s.values)
-	all := make([]string, sl)
-	copy(all, s.values)
-	return all
-}
-
-// AllReverse implements the StringStack interface.
-func (s *stringStack) AllReverse() []string {
-	sl := len(s.values)
-	all := make([]string, sl)
-	for i, value := range s.values {
-		all[sl-1-i] = value
-	}
-	return all
-}
-
-// Len implements the Base interface.
-func (s *stringStack) Len() int {
-	return len(s.values)
-}
-
-// Deflate implements the Base interface.
-func (s *stringStack) Deflate() {
-	s.values = []string{}
-}
-
-// Deflate implements the Stringer interface.
-func (s *stringStack) String() string {
-	return fmt.Sprintf("%v", s.values)
+	return NewTypedStack(vs...)
 }
 
 // EOF
@@ -127,4 +127,30 @@ func TestStringStackPushPop(t *testing.T) {
 	assert.Length(sa, 0)
 }
 
+// TestTypedStackPushPop tests the core typed stack methods.
+func TestTypedStackPushPop(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	s := collections.NewTypedStack(1, 2)
+	assert.Length(s, 2)
+	s.Push(3, 4)
+	assert.Length(s, 4)
+	v, err := s.Peek()
+	assert.Nil(err)
+	assert.Equal(v, 4)
+	v, err = s.Pop()
+	assert.Nil(err)
+	assert.Equal(v, 4)
+	assert.Equal(s.All(), []int{1, 2, 3})
+	assert.Equal(s.AllReverse(), []int{3, 2, 1})
+	assert.Equal(s.String(), "[1 2 3]")
+
+	// Popping an empty stack returns the zero value.
+	s.Deflate()
+	assert.Length(s, 0)
+	v, err = s.Pop()
+	assert.ErrorMatch(err, ".*collection is empty")
+	assert.Equal(v, 0)
+}
+
 // EOF
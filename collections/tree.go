@@ -344,6 +344,122 @@ func (n *node) String() string {
 	return out
 }
 
+//--------------------
+// TYPED TREE
+//--------------------
+
+// typedTree implements the TypedTree interface.
+type typedTree[T comparable] struct {
+	container *nodeContainer
+}
+
+// NewTypedTree creates a new tree for values of type T with
+// or without duplicate values for children.
+func NewTypedTree[T comparable](v T, duplicates bool) TypedTree[T] {
+	return &typedTree[T]{
+		container: newNodeContainer(justValue{v}, duplicates),
+	}
+}
+
+// At implements the TypedTree interface.
+func (t *typedTree[T]) At(values ...T) TypedChanger[T] {
+	var path []nodeContent
+	for _, value := range values {
+		path = append(path, justValue{value})
+	}
+	n, err := t.container.root.at(path...)
+	return &typedChanger[T]{n, err}
+}
+
+// Root implements the TypedTree interface.
+func (t *typedTree[T]) Root() TypedChanger[T] {
+	return &typedChanger[T]{t.container.root, nil}
+}
+
+// Create implements the TypedTree interface.
+func (t *typedTree[T]) Create(values ...T) TypedChanger[T] {
+	var path []nodeContent
+	for _, value := range values {
+		path = append(path, justValue{value})
+	}
+	n, err := t.container.root.create(path...)
+	return &typedChanger[T]{n, err}
+}
+
+// FindFirst implements the TypedTree interface.
+func (t *typedTree[T]) FindFirst(f func(v T) (bool, error)) TypedChanger[T] {
+	n, err := t.container.root.findFirst(func(fn *node) (bool, error) {
+		return f(typedValue[T](fn))
+	})
+	return &typedChanger[T]{n, err}
+}
+
+// FindAll implements the TypedTree interface.
+func (t *typedTree[T]) FindAll(f func(v T) (bool, error)) []TypedChanger[T] {
+	ns, err := t.container.root.findAll(func(fn *node) (bool, error) {
+		return f(typedValue[T](fn))
+	})
+	if err != nil {
+		return []TypedChanger[T]{&typedChanger[T]{nil, err}}
+	}
+	var cs []TypedChanger[T]
+	for _, n := range ns {
+		cs = append(cs, &typedChanger[T]{n, nil})
+	}
+	return cs
+}
+
+// DoAll implements the TypedTree interface.
+func (t *typedTree[T]) DoAll(f func(v T) error) error {
+	return t.container.root.doAll(func(dn *node) error {
+		return f(typedValue[T](dn))
+	})
+}
+
+// DoAllDeep implements the TypedTree interface.
+func (t *typedTree[T]) DoAllDeep(f func(vs []T) error) error {
+	return t.container.root.doAll(func(dn *node) error {
+		values := []T{}
+		cn := dn
+		for cn != nil {
+			values = append([]T{typedValue[T](cn)}, values...)
+			cn = cn.parent
+		}
+		return f(values)
+	})
+}
+
+// Len implements the TypedTree interface.
+func (t *typedTree[T]) Len() int {
+	return t.container.root.size()
+}
+
+// Copy implements the TypedTree interface.
+func (t *typedTree[T]) Copy() TypedTree[T] {
+	return &typedTree[T]{
+		container: t.container.deepCopy(),
+	}
+}
+
+// Deflate implements the TypedTree interface.
+func (t *typedTree[T]) Deflate(v T) {
+	t.container.root = &node{
+		content: justValue{v},
+	}
+}
+
+// String implements the Stringer interface.
+func (t *typedTree[T]) String() string {
+	return t.container.root.String()
+}
+
+// typedValue returns the value of the node as type T. A
+// nil value is returned as zero value.
+func typedValue[T comparable](n *node) T {
+	v, _ := n.content.value().(T)
+	return v
+}
+
 //--------------------
 // TREE
 //--------------------
@@ -457,109 +573,75 @@ func (t *tree) String() string {
 // STRING TREE
 //--------------------
 
-// stringTree implements the StringTree interface.
+// stringTree implements the StringTree interface by
+// wrapping a TypedTree[string].
 type stringTree struct {
-	container *nodeContainer
+	typed TypedTree[string]
 }
 
 // NewStringTree creates a new string tree with or without
 // duplicate values for children.
 func NewStringTree(v string, duplicates bool) StringTree {
-	return &stringTree{
-		container: newNodeContainer(justValue{v}, duplicates),
-	}
+	return &stringTree{NewTypedTree(v, duplicates)}
 }
 
 // At implements the StringTree interface.
 func (t *stringTree) At(values ...string) StringChanger {
-	var path []nodeContent
-	for _, value := range values {
-		path = append(path, justValue{value})
-	}
-	n, err := t.container.root.at(path...)
-	return &stringChanger{n, err}
+	return t.typed.At(values...)
 }
 
 // Root implements the StringTree interface.
 func (t *stringTree) Root() StringChanger {
-	return &stringChanger{t.container.root, nil}
+	return t.typed.Root()
 }
 
 // Create implements the StringTree interface.
 func (t *stringTree) Create(values ...string) StringChanger {
-	var path []nodeContent
-	for _, value := range values {
-		path = append(path, justValue{value})
-	}
-	n, err := t.container.root.create(path...)
-	return &stringChanger{n, err}
+	return t.typed.Create(values...)
 }
 
 // FindFirst implements the StringTree interface.
 func (t *stringTree) FindFirst(f func(v string) (bool, error)) StringChanger {
-	n, err := t.container.root.findFirst(func(fn *node) (bool, error) {
-		return f(fn.content.value().(string))
-	})
-	return &stringChanger{n, err}
+	return t.typed.FindFirst(f)
 }
 
-// FindFirst implements the StringTree interface.
+// FindAll implements the StringTree interface.
 func (t *stringTree) FindAll(f func(v string) (bool, error)) []StringChanger {
-	ns, err := t.container.root.findAll(func(fn *node) (bool, error) {
-		return f(fn.content.value().(string))
-	})
-	if err != nil {
-		return []StringChanger{&stringChanger{nil, err}}
-	}
 	var cs []StringChanger
-	for _, n := range ns {
-		cs = append(cs, &stringChanger{n, nil})
+	for _, c := range t.typed.FindAll(f) {
+		cs = append(cs, c)
 	}
 	return cs
 }
 
 // DoAll implements the StringTree interface.
 func (t *stringTree) DoAll(f func(v string) error) error {
-	return t.container.root.doAll(func(dn *node) error {
-		return f(dn.content.value().(string))
-	})
+	return t.typed.DoAll(f)
 }
 
 // DoAllDeep implements the StringTree interface.
 func (t *stringTree) DoAllDeep(f func(vs []string) error) error {
-	return t.container.root.doAll(func(dn *node) error {
-		values := []string{}
-		cn := dn
-		for cn != nil {
-			values = append([]string{cn.content.value().(string)}, values...)
-			cn = cn.parent
-		}
-		return f(values)
-	})
+	return t.typed.DoAllDeep(f)
 }
 
 // Len implements the StringTree interface.
 func (t *stringTree) Len() int {
-	return t.container.root.size()
+	return t.typed.Len()
 }
 
 // Copy implements the StringTree interface.
 func (t *stringTree) Copy() StringTree {
-	return &stringTree{
-		container: t.container.deepCopy(),
-	}
+	return &stringTree{t.typed.Copy()}
 }
 
 // Deflate implements the StringTree interface.
 func (t *stringTree) Deflate(v string) {
-	t.container.root = &node{
-		content: justValue{v},
-	}
+	t.typed.Deflate(v)
 }
 
 // String implements the Stringer interface.
 func (t *stringTree) String() string {
-	return t.container.root.String()
+	return t.typed.String()
 }
 
 //--------------------
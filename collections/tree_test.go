@@ -943,4 +943,70 @@ func createKeyStringValueTree(assert audit.Assertion) collections.KeyStringValue
 	return tree
 }
 
+//--------------------
+// TEST TYPED TREE
+//--------------------
+
+// TestTypedTree tests the typed tree.
+func TestTypedTree(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+
+	tree := collections.NewTypedTree(0, false)
+	err := tree.Create(0, 1, 11).Add(111)
+	assert.Nil(err)
+	err = tree.Create(0, 2).Add(21)
+	assert.Nil(err)
+	err = tree.At(0, 2).Add(21)
+	assert.ErrorMatch(err, ".* duplicates are not allowed")
+	err = tree.At(0, 3).Add(31)
+	assert.ErrorMatch(err, ".* node not found")
+	assert.Length(tree, 6)
+	assert.Equal(tree.String(), "[0 [1 [11 [111]]][2 [21]]]")
+
+	vs, err := tree.Root().List()
+	assert.Nil(err)
+	assert.Equal(vs, []int{1, 2})
+	old, err := tree.At(0, 2).SetValue(3)
+	assert.Nil(err)
+	assert.Equal(old, 2)
+	v, err := tree.At(0, 3, 21).Value()
+	assert.Nil(err)
+	assert.Equal(v, 21)
+
+	changer := tree.FindFirst(func(v int) (bool, error) {
+		return v > 100, nil
+	})
+	v, err = changer.Value()
+	assert.Nil(err)
+	assert.Equal(v, 111)
+	changers := tree.FindAll(func(v int) (bool, error) {
+		return v > 10, nil
+	})
+	assert.Length(changers, 3)
+
+	sum := 0
+	err = tree.DoAll(func(v int) error {
+		sum += v
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(sum, 147)
+	var deepest []int
+	err = tree.DoAllDeep(func(vs []int) error {
+		if len(vs) > len(deepest) {
+			deepest = vs
+		}
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(deepest, []int{0, 1, 11, 111})
+
+	// Copies are independent.
+	ctree := tree.Copy()
+	err = ctree.At(0, 1, 11, 111).Remove()
+	assert.Nil(err)
+	assert.Length(ctree, 5)
+	assert.Length(tree, 6)
+}
+
 // EOF
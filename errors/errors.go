@@ -236,7 +236,9 @@ type callInfo struct {
 func retrieveCallInfo() *callInfo {
 	pc, file, line, _ := runtime.Caller(3)
 	_, fileName := path.Split(file)
-	parts := strings.Split(runtime.FuncForPC(pc).Name(), ".")
+	// Remove type parameters of generic functions first.
+	funcPath := strings.Replace(runtime.FuncForPC(pc).Name(), "[...]", "", -1)
+	parts := strings.Split(funcPath, ".")
 	pl := len(parts)
 	packageName := ""
 	funcName := parts[pl-1]
@@ -0,0 +1,63 @@
+// Tideland Go Library - Errors - Unit Tests
+//
+// Copyright (C) 2013-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package errors_test
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"testing"
+
+	"github.com/tideland/golib/audit"
+	"github.com/tideland/golib/errors"
+)
+
+//--------------------
+// TESTS
+//--------------------
+
+// TestGenericLocation tests the location of errors
+// created in generic functions and methods.
+func TestGenericLocation(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	ec := 42
+	messages := errors.Messages{ec: "generic %v"}
+
+	err := newGenericError(ec, messages, 1)
+	assert.ErrorMatch(err, `\[ERRORS_TEST:042\] generic 1`)
+	packageName, fileName, _, lerr := errors.Location(err)
+	assert.Nil(lerr)
+	assert.Equal(packageName, "github.com/tideland/golib/errors_test")
+	assert.Equal(fileName, "generics_test.go")
+
+	gb := &genericBox[string]{"two"}
+	err = gb.newError(ec, messages)
+	assert.ErrorMatch(err, `\[ERRORS_TEST:042\] generic two`)
+}
+
+//--------------------
+// HELPERS
+//--------------------
+
+// newGenericError creates an error inside a generic function.
+func newGenericError[T any](ec int, messages errors.Messages, v T) error {
+	return errors.New(ec, messages, v)
+}
+
+// genericBox is a generic type creating errors.
+type genericBox[T any] struct {
+	v T
+}
+
+// newError creates an error inside a generic method.
+func (gb *genericBox[T]) newError(ec int, messages errors.Messages) error {
+	return errors.New(ec, messages, gb.v)
+}
+
+// EOF
@@ -17,8 +17,24 @@ import (
 	"github.com/tideland/golib/errors"
 	"github.com/tideland/golib/identifier"
 	"github.com/tideland/golib/loop"
+	"github.com/tideland/golib/timex"
 )
 
+//--------------------
+// OPTIONS
+//--------------------
+
+// Option allows to configure a Scene.
+type Option func(s *scene)
+
+// Clock returns the option to set the clock used for the
+// timeouts of a Scene. Default is the real clock.
+func Clock(clock timex.Clock) Option {
+	return func(s *scene) {
+		s.clock = clock
+	}
+}
+
 //--------------------
 // SCENE
 //--------------------
@@ -132,33 +148,42 @@ type Scene interface {
 
 // scene implements Scene.
 type scene struct {
-	id          identifier.UUID
-	props       map[string]*box
-	flags       map[string]bool
-	signalings  map[string][]chan struct{}
-	inactivity  time.Duration
-	absolute    time.Duration
-	commandChan chan *envelope
-	backend     loop.Loop
+	id           identifier.UUID
+	props        map[string]*box
+	flags        map[string]bool
+	signalings   map[string][]chan struct{}
+	clock        timex.Clock
+	inactivity   time.Duration
+	absolute     time.Duration
+	clapperboard <-chan time.Time
+	commandChan  chan *envelope
+	backend      loop.Loop
 }
 
 // Start creates and runs a new scene.
-func Start() Scene {
-	return StartLimited(0, 0)
+func Start(options ...Option) Scene {
+	return StartLimited(0, 0, options...)
 }
 
 // StartLimited creates and runs a new scene with an inactivity
 // and an absolute timeout. They may be zero.
-func StartLimited(inactivity, absolute time.Duration) Scene {
+func StartLimited(inactivity, absolute time.Duration, options ...Option) Scene {
 	s := &scene{
 		id:          identifier.NewUUID(),
 		props:       make(map[string]*box),
 		flags:       make(map[string]bool),
 		signalings:  make(map[string][]chan struct{}),
+		clock:       timex.RealClock(),
 		inactivity:  inactivity,
 		absolute:    absolute,
 		commandChan: make(chan *envelope, 1),
 	}
+	for _, option := range options {
+		option(s)
+	}
+	if s.absolute > 0 {
+		s.clapperboard = s.clock.After(s.absolute)
+	}
 	s.backend = loop.Go(s.backendLoop, "scene", s.id.String())
 	return s
 }
@@ -316,7 +341,7 @@ func (s *scene) WaitFlagLimited(topic string, timeout time.Duration) error {
 	// Wait for signal.
 	var timeoutChan <-chan time.Time
 	if timeout > 0 {
-		timeoutChan = time.After(timeout)
+		timeoutChan = s.clock.After(timeout)
 	}
 	select {
 	case <-s.backend.IsStopping():
@@ -378,25 +403,27 @@ func (s *scene) backendLoop(l loop.Loop) (err error) {
 		}
 	}()
 	// Init timers.
-	var watchdog <-chan time.Time
-	var clapperboard <-chan time.Time
-	if s.absolute > 0 {
-		clapperboard = time.After(s.absolute)
+	var watchdog timex.Timer
+	var watchdogC <-chan time.Time
+	if s.inactivity > 0 {
+		watchdog = s.clock.NewTimer(s.inactivity)
+		watchdogC = watchdog.C()
+		defer watchdog.Stop()
 	}
 	// Run loop.
 	for {
-		if s.inactivity > 0 {
-			watchdog = time.After(s.inactivity)
-		}
 		select {
 		case <-l.ShallStop():
 			return nil
-		case timeout := <-watchdog:
+		case timeout := <-watchdogC:
 			return errors.New(ErrTimeout, errorMessages, "inactivity", timeout)
-		case timeout := <-clapperboard:
+		case timeout := <-s.clapperboard:
 			return errors.New(ErrTimeout, errorMessages, "absolute", timeout)
 		case command := <-s.commandChan:
 			s.processCommand(command)
+			if watchdog != nil {
+				watchdog.Reset(s.inactivity)
+			}
 		}
 	}
 }
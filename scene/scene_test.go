@@ -13,11 +13,13 @@ package scene_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/tideland/golib/audit"
 	"github.com/tideland/golib/scene"
+	"github.com/tideland/golib/timex"
 )
 
 //--------------------
@@ -394,4 +396,72 @@ func TestFlagUnflag(t *testing.T) {
 	assert.Nil(err)
 }
 
+// TestFakeClockTimeouts tests the timeouts controlled
+// by a fake clock.
+func TestFakeClockTimeouts(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	clock := timex.NewFakeClock(time.Now())
+	scn := scene.StartLimited(0, time.Hour, scene.Clock(clock))
+
+	err := scn.Store("foo", 4711)
+	assert.Nil(err)
+	clock.Advance(59 * time.Minute)
+	foo, err := scn.Fetch("foo")
+	assert.Nil(err)
+	assert.Equal(foo, 4711)
+
+	clock.Advance(time.Minute)
+	assert.Retry(func() bool {
+		_, err := scn.Fetch("foo")
+		return scene.IsTimeoutError(err)
+	}, 100, 10*time.Millisecond)
+
+	clock = timex.NewFakeClock(time.Now())
+	scn = scene.StartLimited(time.Minute, 0, scene.Clock(clock))
+
+	assert.Retry(func() bool {
+		return clock.Timers() > 0
+	}, 100, time.Millisecond)
+	for i := 0; i < 5; i++ {
+		// Each command resets the single watchdog.
+		clock.Advance(45 * time.Second)
+		err = scn.Store(fmt.Sprintf("foo-%d", i), i)
+		assert.Nil(err)
+		_, err = scn.Fetch(fmt.Sprintf("foo-%d", i))
+		assert.Nil(err)
+		assert.Equal(clock.Timers(), 1)
+	}
+	clock.Advance(time.Minute)
+	assert.Retry(func() bool {
+		_, err := scn.Fetch("foo")
+		return scene.IsTimeoutError(err)
+	}, 100, 10*time.Millisecond)
+	err = scn.Stop()
+	assert.True(scene.IsTimeoutError(err))
+}
+
+// TestFakeClockFlagTimeout tests the waiting for a signal
+// with a timeout controlled by a fake clock.
+func TestFakeClockFlagTimeout(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	clock := timex.NewFakeClock(time.Now())
+	doneC := audit.MakeSigChan()
+	scn := scene.Start(scene.Clock(clock))
+
+	go func() {
+		err := scn.WaitFlagLimited("foo", time.Minute)
+		assert.True(scene.IsWaitedTooLongError(err))
+		doneC <- true
+	}()
+
+	assert.Retry(func() bool {
+		return clock.Timers() > 0
+	}, 100, time.Millisecond)
+	clock.Advance(time.Minute)
+	assert.Wait(doneC, true, time.Second)
+
+	err := scn.Stop()
+	assert.Nil(err)
+}
+
 // EOF
@@ -0,0 +1,388 @@
+// Tideland Go Library - Time Extensions
+//
+// Copyright (C) 2009-2017 Frank Mueller / Tideland / Oldenburg / Germany
+//
+// All rights reserved. Use of this source code is governed
+// by the new BSD license.
+
+package timex
+
+//--------------------
+// IMPORTS
+//--------------------
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+//--------------------
+// CLOCK
+//--------------------
+
+// Ticker delivers ticks of a clock in intervals.
+type Ticker interface {
+	// C returns the channel the ticks are delivered on.
+	C() <-chan time.Time
+
+	// Stop turns off the ticker.
+	Stop()
+}
+
+// Timer delivers the time of a clock once after a duration.
+type Timer interface {
+	// C returns the channel the time is delivered on.
+	C() <-chan time.Time
+
+	// Stop turns off the timer.
+	Stop()
+
+	// Reset stops the timer and lets it deliver the time
+	// after the new duration. A not yet received time is
+	// dropped.
+	Reset(d time.Duration)
+}
+
+// Clock abstracts the access to the current time, timers, and
+// tickers. This way code using it can be tested with a FakeClock
+// instead of waiting for real time passing.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends
+	// the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// Sleep pauses the current goroutine for the duration.
+	Sleep(d time.Duration)
+
+	// NewTimer returns a new timer sending the time once
+	// after the duration. Other than After it can be stopped
+	// and reset.
+	NewTimer(d time.Duration) Timer
+
+	// NewTicker returns a new ticker sending the time
+	// in intervals of the duration.
+	NewTicker(d time.Duration) Ticker
+}
+
+// realClock implements Clock based on the time package.
+type realClock struct{}
+
+// RealClock returns the clock based on the real time.
+func RealClock() Clock {
+	return realClock{}
+}
+
+// Now implements the Clock interface.
+func (c realClock) Now() time.Time {
+	return time.Now()
+}
+
+// After implements the Clock interface.
+func (c realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Sleep implements the Clock interface.
+func (c realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// NewTimer implements the Clock interface.
+func (c realClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{time.NewTimer(d)}
+}
+
+// NewTicker implements the Clock interface.
+func (c realClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{time.NewTicker(d)}
+}
+
+// realTimer implements Timer based on time.Timer.
+type realTimer struct {
+	timer *time.Timer
+}
+
+// C implements the Timer interface.
+func (t *realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+// Stop implements the Timer interface.
+func (t *realTimer) Stop() {
+	t.timer.Stop()
+}
+
+// Reset implements the Timer interface.
+func (t *realTimer) Reset(d time.Duration) {
+	if !t.timer.Stop() {
+		select {
+		case <-t.timer.C:
+		default:
+		}
+	}
+	t.timer.Reset(d)
+}
+
+// realTicker implements Ticker based on time.Ticker.
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+// C implements the Ticker interface.
+func (t *realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+// Stop implements the Ticker interface.
+func (t *realTicker) Stop() {
+	t.ticker.Stop()
+}
+
+//--------------------
+// FAKE CLOCK
+//--------------------
+
+// FakeClock is a Clock for tests. Its time only changes when
+// it is set or advanced manually. Timers and tickers fire
+// when their time is reached this way.
+//
+//	clock := timex.NewFakeClock(time.Now())
+//	crontab := timex.NewCrontab(time.Minute, timex.UseClock(clock))
+//
+//	crontab.Add("job", job)
+//	clock.Advance(5 * time.Minute)
+type FakeClock struct {
+	mux    sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// fakeTimer is a pending timer or ticker of the fake clock.
+type fakeTimer struct {
+	deadline time.Time
+	period   time.Duration
+	c        chan time.Time
+}
+
+// NewFakeClock creates a fake clock starting at the passed time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now: now,
+	}
+}
+
+// Now implements the Clock interface.
+func (c *FakeClock) Now() time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.now
+}
+
+// After implements the Clock interface.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	t := &fakeTimer{
+		c: make(chan time.Time, 1),
+	}
+	c.start(t, d)
+	return t.c
+}
+
+// Sleep implements the Clock interface. It returns
+// when the clock has been advanced enough.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// NewTimer implements the Clock interface.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	t := &fakeTimer{
+		c: make(chan time.Time, 1),
+	}
+	c.start(t, d)
+	return &fakeTimerHandle{c, t}
+}
+
+// NewTicker implements the Clock interface.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	t := &fakeTimer{
+		deadline: c.now.Add(d),
+		period:   d,
+		c:        make(chan time.Time, 1),
+	}
+	c.timers = append(c.timers, t)
+	return &fakeTicker{c, t}
+}
+
+// Advance moves the time of the clock forward and fires
+// all timers and tickers reaching their time.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.set(c.now.Add(d))
+}
+
+// Set sets the time of the clock and fires all timers
+// and tickers reaching their time.
+func (c *FakeClock) Set(now time.Time) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.set(now)
+}
+
+// Timers returns the number of pending timers and tickers. It
+// helps tests to wait until a goroutine started waiting.
+func (c *FakeClock) Timers() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return len(c.timers)
+}
+
+// set sets the time and fires the timers in order of
+// their deadlines.
+func (c *FakeClock) set(now time.Time) {
+	for {
+		sort.SliceStable(c.timers, func(i, j int) bool {
+			return c.timers[i].deadline.Before(c.timers[j].deadline)
+		})
+		if len(c.timers) == 0 || c.timers[0].deadline.After(now) {
+			break
+		}
+		t := c.timers[0]
+		c.now = t.deadline
+		select {
+		case t.c <- t.deadline:
+		default:
+			// Like real tickers drop ticks for slow receivers.
+		}
+		if t.period > 0 {
+			t.deadline = t.deadline.Add(t.period)
+		} else {
+			c.timers = c.timers[1:]
+		}
+	}
+	c.now = now
+}
+
+// start lets a timer fire after the duration.
+func (c *FakeClock) start(t *fakeTimer, d time.Duration) {
+	if d <= 0 {
+		t.c <- c.now
+		return
+	}
+	t.deadline = c.now.Add(d)
+	c.timers = append(c.timers, t)
+}
+
+// reset removes a timer, drops its not received time,
+// and starts it again.
+func (c *FakeClock) reset(t *fakeTimer, d time.Duration) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.remove(t)
+	select {
+	case <-t.c:
+	default:
+	}
+	c.start(t, d)
+}
+
+// stop removes a timer.
+func (c *FakeClock) stop(t *fakeTimer) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.remove(t)
+}
+
+// remove removes a timer from the pending ones.
+func (c *FakeClock) remove(t *fakeTimer) {
+	for i, ct := range c.timers {
+		if ct == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+// fakeTimerHandle implements Timer for the FakeClock.
+type fakeTimerHandle struct {
+	clock *FakeClock
+	timer *fakeTimer
+}
+
+// C implements the Timer interface.
+func (t *fakeTimerHandle) C() <-chan time.Time {
+	return t.timer.c
+}
+
+// Stop implements the Timer interface.
+func (t *fakeTimerHandle) Stop() {
+	t.clock.stop(t.timer)
+}
+
+// Reset implements the Timer interface.
+func (t *fakeTimerHandle) Reset(d time.Duration) {
+	t.clock.reset(t.timer, d)
+}
+
+// fakeTicker implements Ticker for the FakeClock.
+type fakeTicker struct {
+	clock *FakeClock
+	timer *fakeTimer
+}
+
+// C implements the Ticker interface.
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.timer.c
+}
+
+// Stop implements the Ticker interface.
+func (t *fakeTicker) Stop() {
+	t.clock.stop(t.timer)
+}
+
+//--------------------
+// OPTIONS
+//--------------------
+
+// config contains the configuration of crontabs and retries.
+type config struct {
+	clock Clock
+}
+
+// Option allows to configure a Crontab or a Retry.
+type Option func(c *config)
+
+// UseClock returns the option to set the clock used by
+// a Crontab or a Retry. Default is the real clock.
+func UseClock(clock Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// newConfig creates the configuration with default values
+// and applies the passed options.
+func newConfig(options ...Option) *config {
+	c := &config{
+		clock: RealClock(),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// EOF
@@ -45,15 +45,19 @@ type command struct {
 // parallel.
 type Crontab struct {
 	frequency   time.Duration
+	ticker      Ticker
 	jobs        map[string]Job
 	commandChan chan *command
 	loop        loop.Loop
 }
 
-// NewCrontab creates a cron server.
-func NewCrontab(freq time.Duration) *Crontab {
+// NewCrontab creates a cron server. The clock for the checks
+// can be set with the option UseClock().
+func NewCrontab(freq time.Duration, options ...Option) *Crontab {
+	cfg := newConfig(options...)
 	c := &Crontab{
 		frequency:   freq,
+		ticker:      cfg.clock.NewTicker(freq),
 		jobs:        make(map[string]Job),
 		commandChan: make(chan *command),
 	}
@@ -78,7 +82,7 @@ func (c *Crontab) Remove(id string) {
 
 // backendLoop runs the server backend.
 func (c *Crontab) backendLoop(l loop.Loop) error {
-	ticker := time.NewTicker(c.frequency)
+	defer c.ticker.Stop()
 	for {
 		select {
 		case <-l.ShallStop():
@@ -89,7 +93,7 @@ func (c *Crontab) backendLoop(l loop.Loop) error {
 			} else {
 				delete(c.jobs, cmd.id)
 			}
-		case now := <-ticker.C:
+		case now := <-c.ticker.C():
 			for id, job := range c.jobs {
 				c.do(id, job, now)
 			}
@@ -9,6 +9,11 @@
 // tests it contains a crontab for chronological jobs and a retry
 // function to let code blocks be retried under well defined conditions
 // regarding time and count.
+//
+// The Clock interface abstracts the access to the time. Beside the
+// RealClock the FakeClock allows tests to set and advance the time
+// manually. The crontab and the retry as well as other packages like
+// cache and scene accept a clock as option.
 package timex
 
 // EOF
@@ -62,9 +62,11 @@ func LongAttempt() RetryStrategy {
 }
 
 // Retry executes the passed function until it returns true or an error.
-// These retries are restricted by the retry strategy.
-func Retry(f func() (bool, error), rs RetryStrategy) error {
-	timeout := time.Now().Add(rs.Timeout)
+// These retries are restricted by the retry strategy. The clock for
+// the breaks and the timeout can be set with the option UseClock().
+func Retry(f func() (bool, error), rs RetryStrategy, options ...Option) error {
+	clock := newConfig(options...).clock
+	timeout := clock.Now().Add(rs.Timeout)
 	sleep := rs.Break
 	for i := 0; i < rs.Count; i++ {
 		done, err := f()
@@ -74,10 +76,10 @@ func Retry(f func() (bool, error), rs RetryStrategy) error {
 		if done {
 			return nil
 		}
-		if time.Now().After(timeout) {
+		if clock.Now().After(timeout) {
 			return errors.New(ErrRetriedTooLong, errorMessages, rs.Timeout)
 		}
-		time.Sleep(sleep)
+		clock.Sleep(sleep)
 		sleep += rs.BreakIncrement
 	}
 	return errors.New(ErrRetriedTooOften, errorMessages, rs.Count)
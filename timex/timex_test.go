@@ -190,6 +190,123 @@ func TestRetryTooOften(t *testing.T) {
 	assert.ErrorMatch(err, ".* retried more than .* times")
 }
 
+// TestFakeClock tests the manually controlled clock.
+func TestFakeClock(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	start := time.Date(2017, time.September, 9, 12, 0, 0, 0, time.UTC)
+	clock := timex.NewFakeClock(start)
+
+	assert.Equal(clock.Now(), start)
+	clock.Advance(time.Minute)
+	assert.Equal(clock.Now(), start.Add(time.Minute))
+
+	afterC := clock.After(time.Minute)
+	ticker := clock.NewTicker(20 * time.Second)
+	assert.Equal(clock.Timers(), 2)
+	clock.Advance(30 * time.Second)
+	assert.Equal(<-ticker.C(), start.Add(80*time.Second))
+	select {
+	case <-afterC:
+		assert.Fail("timer fired too early")
+	default:
+	}
+	clock.Advance(30 * time.Second)
+	assert.Equal(<-afterC, start.Add(2*time.Minute))
+	assert.Equal(<-ticker.C(), start.Add(100*time.Second))
+	assert.Equal(clock.Timers(), 1)
+
+	ticker.Stop()
+	assert.Equal(clock.Timers(), 0)
+	clock.Set(start)
+	assert.Equal(clock.Now(), start)
+	assert.Equal(<-clock.After(0), start)
+}
+
+// TestFakeClockTimer tests stopping and resetting timers
+// of the manually controlled clock.
+func TestFakeClockTimer(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	start := time.Date(2017, time.September, 9, 12, 0, 0, 0, time.UTC)
+	clock := timex.NewFakeClock(start)
+
+	timer := clock.NewTimer(time.Minute)
+	assert.Equal(clock.Timers(), 1)
+	clock.Advance(45 * time.Second)
+	timer.Reset(time.Minute)
+	assert.Equal(clock.Timers(), 1)
+	clock.Advance(45 * time.Second)
+	select {
+	case <-timer.C():
+		assert.Fail("timer fired too early")
+	default:
+	}
+	clock.Advance(15 * time.Second)
+	assert.Equal(clock.Timers(), 0)
+
+	// Reset drops the not received time.
+	timer.Reset(time.Minute)
+	assert.Equal(clock.Timers(), 1)
+	clock.Advance(time.Minute)
+	assert.Equal(<-timer.C(), start.Add(165*time.Second))
+
+	timer.Reset(time.Minute)
+	timer.Stop()
+	assert.Equal(clock.Timers(), 0)
+}
+
+// TestCrontabFakeClock tests the crontab with a fake clock.
+func TestCrontabFakeClock(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	start := time.Date(2017, time.September, 9, 12, 0, 0, 0, time.UTC)
+	clock := timex.NewFakeClock(start)
+	executedC := audit.MakeSigChan()
+
+	c := timex.NewCrontab(time.Minute, timex.UseClock(clock))
+	defer c.Stop()
+	c.Add("clock", &clockjob{executedC})
+
+	for i := 1; i <= 5; i++ {
+		clock.Advance(time.Minute)
+		assert.Wait(executedC, start.Add(time.Duration(i)*time.Minute), time.Second)
+	}
+}
+
+// TestRetryFakeClock tests a retry timeout with a fake clock.
+func TestRetryFakeClock(t *testing.T) {
+	assert := audit.NewTestingAssertion(t, true)
+	clock := timex.NewFakeClock(time.Now())
+	doneC := audit.MakeSigChan()
+	count := 0
+
+	go func() {
+		rs := timex.RetryStrategy{
+			Count:          100,
+			Break:          time.Minute,
+			BreakIncrement: time.Minute,
+			Timeout:        time.Hour,
+		}
+		doneC <- timex.Retry(func() (bool, error) {
+			count++
+			return false, nil
+		}, rs, timex.UseClock(clock))
+	}()
+
+	for {
+		select {
+		case err := <-doneC:
+			assert.ErrorMatch(err.(error), ".* retried longer than .*")
+			assert.Equal(count, 12)
+			return
+		default:
+			if clock.Timers() > 0 {
+				clock.Advance(time.Minute)
+			} else {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+}
+
 //--------------------
 // HELPERS
 //--------------------
@@ -216,4 +333,17 @@ func (j *cronjob) Execute() (bool, error) {
 	return true, nil
 }
 
+type clockjob struct {
+	executedC chan interface{}
+}
+
+func (j *clockjob) ShallExecute(t time.Time) bool {
+	j.executedC <- t
+	return false
+}
+
+func (j *clockjob) Execute() (bool, error) {
+	return true, nil
+}
+
 // EOF